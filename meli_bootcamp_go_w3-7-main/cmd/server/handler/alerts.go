@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/alerts"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Alerts struct {
+	alertsService alerts.Service
+}
+
+func NewAlerts(a alerts.Service) *Alerts {
+	return &Alerts{
+		alertsService: a,
+	}
+}
+
+// ListAlerts godoc
+// @Summary List temperature alerts
+// @Tag Alerts
+// @Description get the temperature excursion alerts, optionally filtered by status
+// @Produce json
+// @Param status query string false "open or closed"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Router /api/v1/alerts [get]
+func (a *Alerts) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		result, err := a.alertsService.GetAll(ctx, c.Query("status"))
+		if err != nil {
+			if errors.Is(err, alerts.ErrInvalidStatus) {
+				web.Error(c, http.StatusBadRequest, "%s", err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
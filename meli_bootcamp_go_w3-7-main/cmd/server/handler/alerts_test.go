@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/alerts"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type AlertsServiceMock struct {
+	mock.Mock
+}
+
+func (m *AlertsServiceMock) GetAll(ctx context.Context, status string) ([]domain.Alert, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]domain.Alert), args.Error(1)
+}
+
+func (m *AlertsServiceMock) EvaluateSection(ctx context.Context, sectionId int) ([]domain.Alert, error) {
+	args := m.Called(ctx, sectionId)
+	return args.Get(0).([]domain.Alert), args.Error(1)
+}
+
+func createAlertsServer(a *Alerts) *gin.Engine {
+	r := gin.Default()
+	r.GET("/api/v1/alerts", a.GetAll())
+	return r
+}
+
+func TestGetAllAlerts(t *testing.T) {
+	service := new(AlertsServiceMock)
+	service.On("GetAll", mock.Anything, "open").Return([]domain.Alert{{ID: 1, Type: alerts.AlertTypeSection, SectionID: 1, Status: alerts.StatusOpen}}, nil)
+	r := createAlertsServer(NewAlerts(service))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/alerts?status=open", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestGetAllInvalidStatusAlerts(t *testing.T) {
+	service := new(AlertsServiceMock)
+	service.On("GetAll", mock.Anything, "pending").Return([]domain.Alert{}, alerts.ErrInvalidStatus)
+	r := createAlertsServer(NewAlerts(service))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/alerts?status=pending", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -1,35 +1,227 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/buyer"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
 	"github.com/gin-gonic/gin"
 )
 
 type Buyer struct {
-	// buyerService buyer.Service
+	buyerService buyer.Service
 }
 
-func NewBuyer() *Buyer {
+func NewBuyer(b buyer.Service) *Buyer {
 	return &Buyer{
-		// buyerService: b,
+		buyerService: b,
 	}
 }
 
-func (b *Buyer) Get() gin.HandlerFunc {
-	return func(c *gin.Context) {}
+// ListBuyers godoc
+// @Summary List buyers
+// @Tag Buyers
+// @Description get all buyers
+// @Accept json
+// @Produce json
+// @Success 200 {object} web.response
+// @Router /api/v1/buyers [get]
+func (b *Buyer) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		buyers, err := b.buyerService.GetAll(ctx)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		if len(buyers) == 0 {
+			web.Error(c, http.StatusNotFound, "%s", "No existen buyers")
+			return
+		}
+		web.Success(c, http.StatusOK, buyers)
+	}
 }
 
-func (b *Buyer) GetAll() gin.HandlerFunc {
-	return func(c *gin.Context) {}
+// GetBuyer godoc
+// @Summary      Get Buyer
+// @Description  get Buyer by ID
+// @Tags         buyers
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Buyer ID"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/buyers/{id} [get]
+func (b *Buyer) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		buyerById, err := b.buyerService.Get(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, buyer.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el buyer con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, buyerById)
+	}
 }
 
+// CreateBuyer godoc
+// @Summary Create buyer
+// @Tag Buyers
+// @Description create a new buyer
+// @Accept json
+// @Produce json
+// @Success 201 {object} web.response
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/buyers [post]
 func (b *Buyer) Create() gin.HandlerFunc {
-	return func(c *gin.Context) {}
+	type request struct {
+		CardNumberID string `json:"card_number_id" binding:"required"`
+		FirstName    string `json:"first_name" binding:"required"`
+		LastName     string `json:"last_name" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "Los campos card_number_id, first_name y last_name son requeridos")
+			return
+		}
+		ctx := context.Background()
+		newBuyer, err := b.buyerService.Save(ctx, domain.Buyer{
+			CardNumberID: req.CardNumberID,
+			FirstName:    req.FirstName,
+			LastName:     req.LastName,
+		})
+		if err != nil {
+			if errors.Is(err, buyer.ErrAlreadyExists) {
+				web.Error(c, http.StatusConflict, "%s", "Ya existe un buyer con ese card_number_id")
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusCreated, newBuyer)
+	}
 }
 
+// UpdateBuyer godoc
+// @Summary Update buyer
+// @Tag Buyers
+// @Description update first_name and last_name of a buyer
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Buyer ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/buyers/{id} [patch]
 func (b *Buyer) Update() gin.HandlerFunc {
-	return func(c *gin.Context) {}
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		req := domain.Buyer{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusBadRequest, "Error en los datos de la petición: %s", err)
+			return
+		}
+		ctx := context.Background()
+		updatedBuyer, err := b.buyerService.Update(ctx, int(id), req)
+		if err != nil {
+			if errors.Is(err, buyer.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el buyer con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, updatedBuyer)
+	}
 }
 
+// DeleteBuyer godoc
+// @Summary Delete a buyer
+// @Tag Buyers
+// @Description delete a buyer
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Buyer ID"
+// @Success      204  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/buyers/{id} [delete]
 func (b *Buyer) Delete() gin.HandlerFunc {
-	return func(c *gin.Context) {}
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		err = b.buyerService.Delete(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, buyer.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el buyer con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusNoContent, "")
+	}
+}
+
+// ReportPurchaseOrders godoc
+// @Summary Report purchase orders by buyer
+// @Tag Buyers
+// @Description get the purchase_orders_count of every buyer, or of a single buyer when id is given
+// @Accept json
+// @Produce json
+// @Param id query int false "Buyer ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/buyers/reportPurchaseOrders [get]
+func (b *Buyer) GetReportPO() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idurl := c.Query("id")
+		ctx := context.Background()
+		if idurl == "" {
+			results, err := b.buyerService.GetAllReportPurchaseOrders(ctx)
+			if err != nil {
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+			web.Success(c, http.StatusOK, results)
+			return
+		}
+
+		id, err := strconv.ParseInt(idurl, 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		report, err := b.buyerService.GetReportByBuyerIdPurchaseOrders(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, buyer.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existen resultados para el buyer con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, report)
+	}
 }
@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/buyer"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func createBuyerServer() *gin.Engine {
+	buyerService := NewBuyerServiceMock()
+	b := NewBuyer(buyerService)
+	r := gin.Default()
+
+	buyersGroup := r.Group("/buyers")
+	{
+		buyersGroup.GET("/", b.GetAll())
+		buyersGroup.GET("/:id", b.Get())
+		buyersGroup.GET("/reportPurchaseOrders", b.GetReportPO())
+		buyersGroup.POST("/", b.Create())
+		buyersGroup.PATCH("/:id", b.Update())
+		buyersGroup.DELETE("/:id", b.Delete())
+	}
+	return r
+}
+
+func createBuyerRequestTest(method string, url string, body string) (*http.Request, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, url, bytes.NewBuffer([]byte(body)))
+	req.Header.Add("Content-Type", "application/json")
+	return req, httptest.NewRecorder()
+}
+
+type BuyerServiceMock struct {
+	Data []byte
+}
+
+func NewBuyerServiceMock() *BuyerServiceMock {
+	datosDePrueba := []domain.Buyer{
+		{
+			ID:           1,
+			CardNumberID: "402323",
+			FirstName:    "Jhon",
+			LastName:     "Doe",
+		},
+		{
+			ID:           2,
+			CardNumberID: "402324",
+			FirstName:    "Jane",
+			LastName:     "Doe",
+		},
+	}
+	datos, _ := json.Marshal(datosDePrueba)
+	return &BuyerServiceMock{
+		Data: datos,
+	}
+}
+
+func (s *BuyerServiceMock) GetAll(ctx context.Context) ([]domain.Buyer, error) {
+	var datos []domain.Buyer
+	err := json.Unmarshal(s.Data, &datos)
+	if err != nil {
+		return []domain.Buyer{}, err
+	}
+	return datos, nil
+}
+
+func (s *BuyerServiceMock) Get(ctx context.Context, id int) (domain.Buyer, error) {
+	var datos []domain.Buyer
+	err := json.Unmarshal(s.Data, &datos)
+	if err != nil {
+		return domain.Buyer{}, err
+	}
+	for _, b := range datos {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return domain.Buyer{}, buyer.ErrNotFound
+}
+
+func (s *BuyerServiceMock) Save(ctx context.Context, b domain.Buyer) (domain.Buyer, error) {
+	var datos []domain.Buyer
+	err := json.Unmarshal(s.Data, &datos)
+	if err != nil {
+		return domain.Buyer{}, err
+	}
+	for _, d := range datos {
+		if d.CardNumberID == b.CardNumberID {
+			return domain.Buyer{}, buyer.ErrAlreadyExists
+		}
+	}
+	b.ID = datos[len(datos)-1].ID + 1
+	return b, nil
+}
+
+func (s *BuyerServiceMock) Update(ctx context.Context, id int, b domain.Buyer) (domain.Buyer, error) {
+	d, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.Buyer{}, err
+	}
+	if b.FirstName != "" {
+		d.FirstName = b.FirstName
+	}
+	if b.LastName != "" {
+		d.LastName = b.LastName
+	}
+	return d, nil
+}
+
+func (s *BuyerServiceMock) Delete(ctx context.Context, id int) error {
+	_, err := s.Get(ctx, id)
+	return err
+}
+
+func (s *BuyerServiceMock) GetAllReportPurchaseOrders(ctx context.Context) ([]domain.ReportPurchaseOrders, error) {
+	datos, err := s.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var report []domain.ReportPurchaseOrders
+	for _, b := range datos {
+		report = append(report, domain.ReportPurchaseOrders{
+			ID:                  b.ID,
+			CardNumberID:        b.CardNumberID,
+			FirstName:           b.FirstName,
+			LastName:            b.LastName,
+			PurchaseOrdersCount: b.ID,
+		})
+	}
+	return report, nil
+}
+
+func (s *BuyerServiceMock) GetReportByBuyerIdPurchaseOrders(ctx context.Context, buyerId int) (domain.ReportPurchaseOrders, error) {
+	report, err := s.GetAllReportPurchaseOrders(ctx)
+	if err != nil {
+		return domain.ReportPurchaseOrders{}, err
+	}
+	for _, r := range report {
+		if r.ID == buyerId {
+			return r, nil
+		}
+	}
+	return domain.ReportPurchaseOrders{}, buyer.ErrNotFound
+}
+
+func TestBuyerCreateOk(t *testing.T) {
+	r := createBuyerServer()
+	body := `{"card_number_id": "402325", "first_name": "John", "last_name": "Smith"}`
+	req, rr := createBuyerRequestTest(http.MethodPost, "/buyers/", body)
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code, rr.Result())
+}
+
+func TestBuyerCreateFail(t *testing.T) {
+	r := createBuyerServer()
+	body := `{"first_name": "John", "last_name": "Smith"}`
+	req, rr := createBuyerRequestTest(http.MethodPost, "/buyers/", body)
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, rr.Result())
+}
+
+func TestBuyerCreateConflict(t *testing.T) {
+	r := createBuyerServer()
+	body := `{"card_number_id": "402323", "first_name": "John", "last_name": "Smith"}`
+	req, rr := createBuyerRequestTest(http.MethodPost, "/buyers/", body)
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code, rr.Result())
+}
+
+func TestBuyerFindAll(t *testing.T) {
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodGet, "/buyers/", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Result())
+}
+
+func TestBuyerFindByIdNonExistent(t *testing.T) {
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodGet, "/buyers/4", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code, rr.Result())
+}
+
+func TestBuyerFindByIdExistent(t *testing.T) {
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodGet, "/buyers/2", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, rr.Result())
+}
+
+func TestBuyerUpdateOk(t *testing.T) {
+	type response struct {
+		Data domain.Buyer
+	}
+	r := createBuyerServer()
+	body := `{"last_name": "Smith"}`
+	req, rr := createBuyerRequestTest(http.MethodPatch, "/buyers/2", body)
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var objRes response
+	err := json.Unmarshal(rr.Body.Bytes(), &objRes)
+	assert.Nil(t, err)
+	assert.Equal(t, "Jane", objRes.Data.FirstName)
+	assert.Equal(t, "Smith", objRes.Data.LastName)
+}
+
+func TestBuyerUpdateNonExistent(t *testing.T) {
+	r := createBuyerServer()
+	body := `{"last_name": "Smith"}`
+	req, rr := createBuyerRequestTest(http.MethodPatch, "/buyers/5", body)
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestBuyerDeleteNonExistent(t *testing.T) {
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodDelete, "/buyers/4", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code, rr.Result())
+}
+
+func TestBuyerDeleteOk(t *testing.T) {
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodDelete, "/buyers/1", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code, rr.Result())
+}
+
+func TestBuyerReportPurchaseOrdersAll(t *testing.T) {
+	type response struct {
+		Data []domain.ReportPurchaseOrders
+	}
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodGet, "/buyers/reportPurchaseOrders", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var objRes response
+	err := json.Unmarshal(rr.Body.Bytes(), &objRes)
+	assert.Nil(t, err)
+	assert.Len(t, objRes.Data, 2)
+}
+
+func TestBuyerReportPurchaseOrdersById(t *testing.T) {
+	type response struct {
+		Data domain.ReportPurchaseOrders
+	}
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodGet, "/buyers/reportPurchaseOrders?id=2", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var objRes response
+	err := json.Unmarshal(rr.Body.Bytes(), &objRes)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, objRes.Data.PurchaseOrdersCount)
+}
+
+func TestBuyerReportPurchaseOrdersNoResult(t *testing.T) {
+	r := createBuyerServer()
+	req, rr := createBuyerRequestTest(http.MethodGet, "/buyers/reportPurchaseOrders?id=9", "")
+
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
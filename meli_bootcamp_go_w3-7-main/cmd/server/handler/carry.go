@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"strconv"
 
 	"net/http"
@@ -219,6 +220,39 @@ func (c *Carry) GetCarriesReport() gin.HandlerFunc {
 
 }
 
+// ListEligibleCarries godoc
+// @Summary List eligible carries
+// @Tag Carries
+// @Description get the carries serving a locality, ranked by open shipments
+// @Accept json
+// @Produce json
+// @Param        locality_id  query  int  true  "Locality ID"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/carries/eligible [get]
+func (c *Carry) GetEligible() gin.HandlerFunc {
+	return func(con *gin.Context) {
+		id, err := strconv.Atoi(con.Query("locality_id"))
+		if err != nil {
+			web.Error(con, http.StatusBadRequest, "%s", "El locality_id ingresado no es válido")
+			return
+		}
+
+		ctx := context.Background()
+		carries, err := c.carryService.GetEligible(ctx, id)
+		if err != nil {
+			if errors.Is(err, carry.ErrLocalityNotFound) {
+				web.Error(con, http.StatusNotFound, "No existe un locality con el id: %d", id)
+				return
+			}
+			web.Error(con, http.StatusInternalServerError, "Se presentó un problema en la consulta: %s", err)
+			return
+		}
+		web.Success(con, http.StatusOK, carries)
+	}
+}
+
 func updateCarryFields(lastC domain.Carry, newC domain.Carry, id int) domain.Carry {
 	if newC.CID != lastC.CID && newC.CID != "" {
 		lastC.CID = newC.CID
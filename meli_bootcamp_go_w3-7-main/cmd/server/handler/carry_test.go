@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/carry"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type dbCarryMock struct {
+	carry.Repository
+	mock.Mock
+}
+
+func (m *dbCarryMock) ExistsLocality(ctx context.Context, localityId int) bool {
+	args := m.Called(ctx, localityId)
+	return args.Bool(0)
+}
+
+func (m *dbCarryMock) GetEligible(ctx context.Context, localityId int) ([]domain.EligibleCarry, error) {
+	args := m.Called(ctx, localityId)
+	return args.Get(0).([]domain.EligibleCarry), args.Error(1)
+}
+
+func createServiceCarry(c *Carry) *gin.Engine {
+	r := gin.Default()
+	r.GET("/api/v1/carries/eligible", c.GetEligible())
+	return r
+}
+
+func TestGetEligibleCarriesLocalityNotFound(t *testing.T) {
+	repo := new(dbCarryMock)
+	repo.On("ExistsLocality", mock.Anything, 9).Return(false)
+	r := createServiceCarry(NewCarry(carry.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/carries/eligible?locality_id=9", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	repo.AssertNotCalled(t, "GetEligible", mock.Anything, mock.Anything)
+}
+
+func TestGetEligibleCarriesInvalidLocality(t *testing.T) {
+	r := createServiceCarry(NewCarry(carry.NewService(new(dbCarryMock))))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/carries/eligible?locality_id=abc", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetEligibleCarriesOk(t *testing.T) {
+	repo := new(dbCarryMock)
+	repo.On("ExistsLocality", mock.Anything, 1).Return(true)
+	repo.On("GetEligible", mock.Anything, 1).Return([]domain.EligibleCarry{{Carry: domain.Carry{ID: 2, LocalityId: 1}}}, nil)
+	r := createServiceCarry(NewCarry(carry.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/carries/eligible?locality_id=1", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"open_shipments":0`)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/country"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Country struct {
+	countryService country.Service
+}
+
+func NewCountry(c country.Service) *Country {
+	return &Country{
+		countryService: c,
+	}
+}
+
+// ListCountries godoc
+// @Summary List countries
+// @Tag Countries
+// @Description get all countries
+// @Accept json
+// @Produce json
+// @Success 200 {object} web.response
+// @Router /api/v1/countries [get]
+func (co *Country) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		countries, err := co.countryService.GetAll(ctx)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, countries)
+	}
+}
+
+// GetCountry godoc
+// @Summary      Get Country
+// @Description  get Country by ID
+// @Tags         countries
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Country ID"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/countries/{id} [get]
+func (co *Country) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		result, err := co.countryService.Get(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, country.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el country con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// CreateCountry godoc
+// @Summary Create country
+// @Tag Countries
+// @Description create a new country
+// @Accept json
+// @Produce json
+// @Success 201 {object} web.response
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/countries [post]
+func (co *Country) Create() gin.HandlerFunc {
+	type request struct {
+		CountryName string `json:"country_name" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo country_name es requerido")
+			return
+		}
+		ctx := context.Background()
+		result, err := co.countryService.Save(ctx, domain.Country{CountryName: req.CountryName})
+		if err != nil {
+			if errors.Is(err, country.ErrAlreadyExists) {
+				web.Error(c, http.StatusConflict, "%s", "Ya existe un country con ese nombre")
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusCreated, result)
+	}
+}
+
+// UpdateCountry godoc
+// @Summary Update country
+// @Tag Countries
+// @Description update the name of a country
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Country ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Failure 409 {object} web.errorResponse
+// @Router /api/v1/countries/{id} [patch]
+func (co *Country) Update() gin.HandlerFunc {
+	type request struct {
+		CountryName string `json:"country_name" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo country_name es requerido")
+			return
+		}
+		ctx := context.Background()
+		result, err := co.countryService.Update(ctx, domain.Country{ID: int(id), CountryName: req.CountryName})
+		if err != nil {
+			switch {
+			case errors.Is(err, country.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe el country con el id %d", id)
+				return
+			case errors.Is(err, country.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, "%s", "Ya existe un country con ese nombre")
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// DeleteCountry godoc
+// @Summary Delete a country
+// @Tag Countries
+// @Description delete a country without provinces
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Country ID"
+// @Success      204  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Failure      409  {object}  web.errorResponse
+// @Router       /api/v1/countries/{id} [delete]
+func (co *Country) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		err = co.countryService.Delete(ctx, int(id))
+		if err != nil {
+			switch {
+			case errors.Is(err, country.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe el country con el id %d", id)
+				return
+			case errors.Is(err, country.ErrHasProvinces):
+				web.Error(c, http.StatusConflict, "%s", "El country tiene provinces asociadas")
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusNoContent, "")
+	}
+}
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/employee"
@@ -92,12 +94,16 @@ func (e *Employee) Create() gin.HandlerFunc {
 			return
 		}
 		ctx := context.Background()
-		employee, err := e.employeeService.Save(ctx, req)
+		result, err := e.employeeService.Save(ctx, req)
+		if errors.Is(err, employee.ErrWarehouseNotFound) {
+			web.Error(c, http.StatusConflict, "No existe el warehouse con el id %d", req.WarehouseID)
+			return
+		}
 		if err != nil {
 			web.Error(c, http.StatusNotFound, "%s", err)
 			return
 		}
-		web.Success(c, http.StatusCreated, employee)
+		web.Success(c, http.StatusCreated, result)
 	}
 }
 
@@ -115,6 +121,10 @@ func (e *Employee) Update() gin.HandlerFunc {
 		}
 		ctx := context.Background()
 		result, err := e.employeeService.Update(ctx, int(id), req)
+		if errors.Is(err, employee.ErrWarehouseNotFound) {
+			web.Error(c, http.StatusConflict, "No existe el warehouse con el id %d", req.WarehouseID)
+			return
+		}
 		if err != nil {
 			web.Error(c, http.StatusNotFound, "Error in code for: %s", err)
 			return
@@ -140,16 +150,93 @@ func (e *Employee) Delete() gin.HandlerFunc {
 	}
 }
 
+// ListEmployeeAssignments godoc
+// @Summary List employee assignments
+// @Tag Employees
+// @Description get the warehouses an employee has been assigned to, oldest first
+// @Produce json
+// @Param        id   path      int  true  "Employee ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/employees/{id}/assignments [get]
+func (e *Employee) GetAssignments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		assignments, err := e.employeeService.GetAssignments(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, employee.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el empleado con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, assignments)
+	}
+}
+
+// ListWarehouseEmployees godoc
+// @Summary List warehouse employees
+// @Tag Employees
+// @Description get the employees working in a warehouse now or at the given date
+// @Produce json
+// @Param        id   path      int     true   "Warehouse ID"
+// @Param        at   query     string  false  "Date (YYYY-MM-DD or RFC3339)"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/warehouses/{id}/employees [get]
+func (e *Employee) GetByWarehouse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		var at time.Time
+		if c.Query("at") != "" {
+			if at, err = parseQueryTime(c.Query("at")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro at debe tener el formato YYYY-MM-DD o RFC3339")
+				return
+			}
+		}
+		ctx := context.Background()
+		employees, err := e.employeeService.GetByWarehouse(ctx, int(id), at)
+		if err != nil {
+			if errors.Is(err, employee.ErrWarehouseNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el warehouse con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, employees)
+	}
+}
+
 func (e *Employee) GetReportIO() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		filter, ok := reportInboundOrdersFilter(c)
+		if !ok {
+			return
+		}
 		idurl := c.Query("id")
 		ctx := context.Background()
 		if idurl == "" {
-			results, err := e.employeeService.GetAllReportInboundOrders(ctx)
+			results, err := e.employeeService.GetAllReportInboundOrders(ctx, filter)
 			/*if len(results) == 0 {
 				web.Error(c, http.StatusNotFound, "%s", "No existen resultados")
 				return
 			}*/
+			if errors.Is(err, employee.ErrInvalidRange) {
+				web.Error(c, http.StatusBadRequest, "%s", err.Error())
+				return
+			}
 			if err != nil {
 				web.Error(c, http.StatusNotFound, "Error in code for: %s", err.Error())
 				return
@@ -163,7 +250,11 @@ func (e *Employee) GetReportIO() gin.HandlerFunc {
 			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
 			return
 		}
-		report, err := e.employeeService.GetReportByEmployeeIdInboundOrders(ctx, int(id))
+		report, err := e.employeeService.GetReportByEmployeeIdInboundOrders(ctx, int(id), filter)
+		if errors.Is(err, employee.ErrInvalidRange) {
+			web.Error(c, http.StatusBadRequest, "%s", err.Error())
+			return
+		}
 		if report.ID == 0 {
 			web.Error(c, http.StatusNotFound, "No existen resultados para el empleado con el id %d", id)
 			return
@@ -177,6 +268,32 @@ func (e *Employee) GetReportIO() gin.HandlerFunc {
 	}
 }
 
+// reportInboundOrdersFilter reads the warehouse_id, from and to query
+// parameters, writing a 400 response and returning false when one is invalid.
+func reportInboundOrdersFilter(c *gin.Context) (domain.ReportInboundOrdersFilter, bool) {
+	filter := domain.ReportInboundOrdersFilter{}
+	var err error
+	if c.Query("warehouse_id") != "" {
+		if filter.WarehouseID, err = strconv.Atoi(c.Query("warehouse_id")); err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: invalid %s", "warehouse_id")
+			return filter, false
+		}
+	}
+	if c.Query("from") != "" {
+		if filter.From, err = parseQueryTime(c.Query("from")); err != nil {
+			web.Error(c, http.StatusBadRequest, "%s", "El parametro from debe tener el formato YYYY-MM-DD o RFC3339")
+			return filter, false
+		}
+	}
+	if c.Query("to") != "" {
+		if filter.To, err = parseQueryEndTime(c.Query("to")); err != nil {
+			web.Error(c, http.StatusBadRequest, "%s", "El parametro to debe tener el formato YYYY-MM-DD o RFC3339")
+			return filter, false
+		}
+	}
+	return filter, true
+}
+
 func validateFields(e domain.Employee) bool {
 	if e.CardNumberID == "" {
 		return true
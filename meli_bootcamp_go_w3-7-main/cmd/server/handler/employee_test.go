@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/employee"
@@ -52,13 +53,28 @@ func (m *dbMock) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
-func (m *dbMock) GetAllReportInboundOrders() ([]domain.ReportInboundOrders, error) {
-	args := m.Called()
+func (m *dbMock) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	args := m.Called(ctx, warehouseId)
+	return args.Bool(0)
+}
+
+func (m *dbMock) GetAssignments(ctx context.Context, employeeId int) ([]domain.EmployeeAssignment, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]domain.EmployeeAssignment), args.Error(1)
+}
+
+func (m *dbMock) GetByWarehouse(ctx context.Context, warehouseId int, at time.Time) ([]domain.Employee, error) {
+	args := m.Called(ctx, warehouseId, at)
+	return args.Get(0).([]domain.Employee), args.Error(1)
+}
+
+func (m *dbMock) GetAllReportInboundOrders(ctx context.Context, filter domain.ReportInboundOrdersFilter) ([]domain.ReportInboundOrders, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]domain.ReportInboundOrders), args.Error(1)
 }
 
-func (m *dbMock) GetReportByEmployeeIdInboundOrders(employeeId int) (domain.ReportInboundOrders, error) {
-	args := m.Called(employeeId)
+func (m *dbMock) GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int, filter domain.ReportInboundOrdersFilter) (domain.ReportInboundOrders, error) {
+	args := m.Called(ctx, employeeId, filter)
 	return args.Get(0).(domain.ReportInboundOrders), args.Error(1)
 }
 
@@ -69,10 +85,12 @@ func createServiceE(p *Employee) *gin.Engine {
 		pr.GET("/", p.GetAll())
 		pr.GET("/:id", p.Get())
 		pr.GET("/reportinboundorders", p.GetReportIO())
+		pr.GET("/:id/assignments", p.GetAssignments())
 		pr.POST("/", p.Create())
 		pr.PATCH("/:id", p.Update())
 		pr.DELETE("/:id", p.Delete())
 	}
+	r.GET("api/v1/warehouses/:id/employees", p.GetByWarehouse())
 	return r
 }
 
@@ -211,6 +229,7 @@ func TestCreateOkEmployee(t *testing.T) {
 	repo := new(dbMock)
 	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
 	repo.On("Exists", mock.Anything, mock.Anything).Return(false)
+	repo.On("ExistsWarehouse", mock.Anything, mock.Anything).Return(true)
 	service := employee.NewService(repo)
 	p := NewEmployee(service)
 	r := createServiceE(p)
@@ -285,6 +304,7 @@ func TestUpdateOK(t *testing.T) {
 	repo := new(dbMock)
 	repo.On("Get", mock.Anything, mock.Anything).Return(expectedResultGet, nil)
 	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	repo.On("ExistsWarehouse", mock.Anything, mock.Anything).Return(true)
 	service := employee.NewService(repo)
 	p := NewEmployee(service)
 	r := createServiceE(p)
@@ -397,7 +417,7 @@ func TestReportGetAllInboudOrders(t *testing.T) {
 		InboundOrdersCount: 3,
 	})
 	repo := new(dbMock)
-	repo.On("GetAllReportInboundOrders").Return(expectedResult, nil)
+	repo.On("GetAllReportInboundOrders", mock.Anything, mock.Anything).Return(expectedResult, nil)
 	service := employee.NewService(repo)
 	p := NewEmployee(service)
 	r := createServiceE(p)
@@ -419,7 +439,7 @@ func TestReportGetAllInboudOrdersNoResult(t *testing.T) {
 	expectedResult := []domain.ReportInboundOrders{}
 	expectedError := "No existen resultados"
 	repo := new(dbMock)
-	repo.On("GetAllReportInboundOrders").Return(expectedResult, errors.New(expectedError))
+	repo.On("GetAllReportInboundOrders", mock.Anything, mock.Anything).Return(expectedResult, errors.New(expectedError))
 	service := employee.NewService(repo)
 	p := NewEmployee(service)
 	r := createServiceE(p)
@@ -445,7 +465,7 @@ func TestInboudOrdersReportGetByEmployee(t *testing.T) {
 		InboundOrdersCount: 3,
 	}
 	repo := new(dbMock)
-	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything).Return(expectedResult, nil)
+	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything, mock.Anything, mock.Anything).Return(expectedResult, nil)
 	service := employee.NewService(repo)
 	p := NewEmployee(service)
 	r := createServiceE(p)
@@ -487,7 +507,7 @@ func TestGetReportInboudOrdersNoResult(t *testing.T) {
 	id := 1
 	expectedError := fmt.Sprintf("No existen resultados para el empleado con el id %d", id)
 	repo := new(dbMock)
-	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything).Return(domain.ReportInboundOrders{}, errors.New("The employee doesn't exist"))
+	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything, mock.Anything, mock.Anything).Return(domain.ReportInboundOrders{}, errors.New("The employee doesn't exist"))
 	service := employee.NewService(repo)
 	p := NewEmployee(service)
 	r := createServiceE(p)
@@ -499,3 +519,118 @@ func TestGetReportInboudOrdersNoResult(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expectedError, objRes.Message)
 }
+
+func TestReportInboudOrdersFiltered(t *testing.T) {
+	type response struct {
+		Data []domain.ReportInboundOrders
+	}
+	from, _ := time.Parse("2006-01-02", "2022-01-06")
+	to, _ := time.Parse("2006-01-02", "2022-01-09")
+	expectedResult := []domain.ReportInboundOrders{
+		{
+			ID:                 1,
+			CardNumberID:       "123456",
+			FirstName:          "laynerker",
+			LastName:           "guerrero",
+			WarehouseID:        5,
+			InboundOrdersCount: 3,
+			Days: []domain.ReportInboundOrdersDay{
+				{Date: "2022-01-06", InboundOrdersCount: 1},
+				{Date: "2022-01-07", InboundOrdersCount: 2},
+			},
+		},
+		{
+			ID:           2,
+			CardNumberID: "654321",
+			FirstName:    "hadassa",
+			LastName:     "guerrero",
+			WarehouseID:  5,
+			Days:         []domain.ReportInboundOrdersDay{},
+		},
+	}
+	repo := new(dbMock)
+	repo.On("GetAllReportInboundOrders", mock.Anything, domain.ReportInboundOrdersFilter{WarehouseID: 5, From: from, To: to}).Return(expectedResult, nil)
+	r := createServiceE(NewEmployee(employee.NewService(repo)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/employees/reportinboundorders?warehouse_id=5&from=2022-01-06&to=2022-01-08", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var objRes response
+	err := json.Unmarshal(rr.Body.Bytes(), &objRes)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResult, objRes.Data)
+}
+
+func TestReportInboudOrdersInvalidFilter(t *testing.T) {
+	repo := new(dbMock)
+	r := createServiceE(NewEmployee(employee.NewService(repo)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/employees/reportinboundorders?warehouse_id=main", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	req, rr = createRequestTest(http.MethodGet, "/api/v1/employees/reportinboundorders?id=1&from=2022-01-08&to=2022-01-06", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreateEmployeeNoExistWarehouse(t *testing.T) {
+	repo := new(dbMock)
+	repo.On("Exists", mock.Anything, mock.Anything).Return(false)
+	repo.On("ExistsWarehouse", mock.Anything, 9).Return(false)
+	r := createServiceE(NewEmployee(employee.NewService(repo)))
+	req, rr := createRequestTest(http.MethodPost, "/api/v1/employees/", `{"card_number_id": "123456","first_name": "laynerker","last_name": "guerrero","warehouse_id": 9}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestGetEmployeeAssignments(t *testing.T) {
+	type response struct {
+		Data []domain.EmployeeAssignment
+	}
+	start, _ := time.Parse("2006-01-02", "2022-01-01")
+	end, _ := time.Parse("2006-01-02", "2022-03-01")
+	expectedResult := []domain.EmployeeAssignment{
+		{ID: 1, EmployeeID: 1, WarehouseID: 5, StartDate: start, EndDate: &end},
+		{ID: 2, EmployeeID: 1, WarehouseID: 6, StartDate: end},
+	}
+	repo := new(dbMock)
+	repo.On("Get", mock.Anything, 1).Return(domain.Employee{ID: 1, WarehouseID: 6}, nil)
+	repo.On("GetAssignments", mock.Anything, 1).Return(expectedResult, nil)
+	r := createServiceE(NewEmployee(employee.NewService(repo)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/employees/1/assignments", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var objRes response
+	err := json.Unmarshal(rr.Body.Bytes(), &objRes)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResult, objRes.Data)
+}
+
+func TestGetEmployeeAssignmentsNonExistent(t *testing.T) {
+	repo := new(dbMock)
+	repo.On("GetAssignments", mock.Anything, 4).Return([]domain.EmployeeAssignment{}, nil)
+	repo.On("Get", mock.Anything, 4).Return(domain.Employee{}, errors.New("sql: no rows in result set"))
+	r := createServiceE(NewEmployee(employee.NewService(repo)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/employees/4/assignments", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetWarehouseEmployeesAt(t *testing.T) {
+	at, _ := time.Parse("2006-01-02", "2022-02-01")
+	repo := new(dbMock)
+	repo.On("ExistsWarehouse", mock.Anything, 5).Return(true)
+	repo.On("GetByWarehouse", mock.Anything, 5, at).Return([]domain.Employee{{ID: 1, WarehouseID: 5}}, nil)
+	r := createServiceE(NewEmployee(employee.NewService(repo)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/warehouses/5/employees?at=2022-02-01", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetWarehouseEmployeesNonExistent(t *testing.T) {
+	repo := new(dbMock)
+	repo.On("ExistsWarehouse", mock.Anything, 9).Return(false)
+	r := createServiceE(NewEmployee(employee.NewService(repo)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/warehouses/9/employees", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
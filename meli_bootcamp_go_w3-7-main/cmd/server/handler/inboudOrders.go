@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,6 +32,7 @@ func (i *InboudOrders) Create() gin.HandlerFunc {
 		EmployeeId     int    `json:"employee_id" binding:"required"`
 		ProductBatchId int    `json:"product_batch_id" binding:"required"`
 		WarehouseID    int    `json:"warehouse_id" binding:"required"`
+		Quantity       int    `json:"quantity" binding:"omitempty,gt=0"`
 	}
 	return func(c *gin.Context) {
 		req := request{}
@@ -41,6 +43,9 @@ func (i *InboudOrders) Create() gin.HandlerFunc {
 			case strings.Contains(string(err.Error()), "required"):
 				web.Error(c, http.StatusUnprocessableEntity, "%s", "Todos los campos son requeridos")
 				return
+			case strings.Contains(string(err.Error()), "gt"):
+				web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo quantity debe ser mayor a 0")
+				return
 			default:
 				web.Error(c, http.StatusNotFound, "Error in code for: %s", err)
 				return
@@ -54,13 +59,23 @@ func (i *InboudOrders) Create() gin.HandlerFunc {
 			EmployeeId:     req.EmployeeId,
 			ProductBatchId: req.ProductBatchId,
 			WarehouseID:    req.WarehouseID,
+			Quantity:       req.Quantity,
 		}
 		inboudOrdersResul, err := i.inboudOrdersService.Save(ctx, newInboudOrders)
 		if err != nil {
 			switch {
-			case errors.Is(err, inboudOrders.ErrorEmployeesNoExist):
+			case errors.Is(err, inboudOrders.ErrorEmployeesNoExist),
+				errors.Is(err, inboudOrders.ErrWarehouseNotFound),
+				errors.Is(err, inboudOrders.ErrProductBatchNotFound),
+				errors.Is(err, inboudOrders.ErrEmployeeNotInWarehouse),
+				errors.Is(err, inboudOrders.ErrProductBatchNotInWarehouse),
+				errors.Is(err, inboudOrders.ErrAlreadyExists),
+				errors.Is(err, inboudOrders.ErrSectionCapacityExceeded):
 				web.Error(c, http.StatusConflict, "%s", err)
 				return
+			case errors.Is(err, inboudOrders.ErrInvalidQuantity):
+				web.Error(c, http.StatusUnprocessableEntity, "%s", err)
+				return
 			default:
 				web.Error(c, http.StatusNotFound, "%s", err)
 				return
@@ -69,3 +84,95 @@ func (i *InboudOrders) Create() gin.HandlerFunc {
 		web.Success(c, http.StatusCreated, inboudOrdersResul)
 	}
 }
+
+// ListInboundOrders godoc
+// @Summary List inbound orders
+// @Tag InboundOrders
+// @Description get a page of inbound orders sorted by order_date, optionally filtered by employee, warehouse and date range
+// @Produce json
+// @Param employee_id  query int    false "Employee ID"
+// @Param warehouse_id query int    false "Warehouse ID"
+// @Param from         query string false "Start date (YYYY-MM-DD or RFC3339), inclusive"
+// @Param to           query string false "End date, exclusive (RFC3339) or inclusive (YYYY-MM-DD)"
+// @Param page         query int    false "Page, starting at 1"
+// @Param limit        query int    false "Page size (default 20, max 100)"
+// @Param order        query string false "asc (default) or desc"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Router /api/v1/inboundOrders [get]
+func (i *InboudOrders) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := domain.InboudOrdersFilter{Order: c.Query("order")}
+		ints := map[string]*int{
+			"employee_id":  &filter.EmployeeId,
+			"warehouse_id": &filter.WarehouseID,
+			"page":         &filter.Page,
+			"limit":        &filter.Limit,
+		}
+		for param, target := range ints {
+			if c.Query(param) == "" {
+				continue
+			}
+			value, err := strconv.Atoi(c.Query(param))
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "Error: invalid %s", param)
+				return
+			}
+			*target = value
+		}
+		var err error
+		if c.Query("from") != "" {
+			if filter.From, err = parseQueryTime(c.Query("from")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro from debe tener el formato YYYY-MM-DD o RFC3339")
+				return
+			}
+		}
+		if c.Query("to") != "" {
+			if filter.To, err = parseQueryEndTime(c.Query("to")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro to debe tener el formato YYYY-MM-DD o RFC3339")
+				return
+			}
+		}
+		ctx := context.Background()
+		page, err := i.inboudOrdersService.GetAll(ctx, filter)
+		if err != nil {
+			if errors.Is(err, inboudOrders.ErrInvalidFilter) {
+				web.Error(c, http.StatusBadRequest, "%s", err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, page)
+	}
+}
+
+// GetInboundOrder godoc
+// @Summary      Get Inbound Order
+// @Description  get Inbound Order by ID
+// @Tags         inboundOrders
+// @Produce      json
+// @Param        id   path      int  true  "Inbound Order ID"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/inboundOrders/{id} [get]
+func (i *InboudOrders) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		order, err := i.inboudOrdersService.Get(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, inboudOrders.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe la inbound order con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, order)
+	}
+}
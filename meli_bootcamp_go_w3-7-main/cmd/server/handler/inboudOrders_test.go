@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -30,10 +31,42 @@ func (m *dbIOMock) ExistsEmployee(ctx context.Context, employeeId int) bool {
 	return args.Bool(0)
 }
 
+func (m *dbIOMock) GetAll(ctx context.Context, filter domain.InboudOrdersFilter) ([]domain.InboudOrders, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.InboudOrders), args.Int(1), args.Error(2)
+}
+
+func (m *dbIOMock) Get(ctx context.Context, id int) (domain.InboudOrders, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.InboudOrders), args.Error(1)
+}
+
+func (m *dbIOMock) Exists(ctx context.Context, orderNumber string) bool {
+	args := m.Called(ctx, orderNumber)
+	return args.Bool(0)
+}
+
+func (m *dbIOMock) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	args := m.Called(ctx, warehouseId)
+	return args.Bool(0)
+}
+
+func (m *dbIOMock) ExistsProductBatch(ctx context.Context, productBatchId int) bool {
+	args := m.Called(ctx, productBatchId)
+	return args.Bool(0)
+}
+
+func (m *dbIOMock) ExistsEmployeeInWarehouse(ctx context.Context, employeeId, warehouseId int) bool {
+	args := m.Called(ctx, employeeId, warehouseId)
+	return args.Bool(0)
+}
+
 func createServiceIO(p *InboudOrders) *gin.Engine {
 	r := gin.Default()
 	pr := r.Group("api/v1/inboundOrders")
 	{
+		pr.GET("/", p.GetAll())
+		pr.GET("/:id", p.Get())
 		pr.POST("/", p.Create())
 	}
 	return r
@@ -57,10 +90,15 @@ func TestCreateOkInboudOrders(t *testing.T) {
 		EmployeeId:     1,
 		ProductBatchId: 2,
 		WarehouseID:    3,
+		Quantity:       10,
 	}
 	repo := new(dbIOMock)
 	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
 	repo.On("ExistsEmployee", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 2).Return(true)
+	repo.On("ExistsEmployeeInWarehouse", mock.Anything, 1, 3).Return(true)
+	repo.On("Exists", mock.Anything, "12312").Return(false)
 	service := inboudOrders.NewService(repo)
 	p := NewInboudOrders(service)
 	r := createServiceIO(p)
@@ -69,7 +107,8 @@ func TestCreateOkInboudOrders(t *testing.T) {
 		"order_number": "12312",
 		"employee_id": 1,
 		"product_batch_id": 2,
-		"warehouse_id": 3
+		"warehouse_id": 3,
+		"quantity": 10
 		}`)
 	r.ServeHTTP(rr, req)
 	assert.Equal(t, http.StatusCreated, rr.Code)
@@ -96,7 +135,8 @@ func TestCreateFailInboudOrders(t *testing.T) {
 		"order_number": "12312",
 		"employee_id": 1,
 		"product_batch_id": 2,
-		"warehouse_id": 3
+		"warehouse_id": 3,
+		"quantity": 10
 		}`)
 	r.ServeHTTP(rr, req)
 	assert.Equal(t, http.StatusConflict, rr.Code)
@@ -125,3 +165,79 @@ func TestCreateFailFieldInboudOrders(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expectedError, objRes.Message)
 }
+
+func TestGetAllFilteredInboudOrders(t *testing.T) {
+	from, _ := time.Parse("2006-01-02", "2022-01-06")
+	to, _ := time.Parse("2006-01-02", "2022-01-08")
+	repo := new(dbIOMock)
+	repo.On("GetAll", mock.Anything, domain.InboudOrdersFilter{EmployeeId: 1, WarehouseID: 3, From: from, To: to, Page: 2, Limit: 5, Order: "desc"}).Return([]domain.InboudOrders{}, 6, nil)
+	r := createServiceIO(NewInboudOrders(inboudOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/inboundOrders/?employee_id=1&warehouse_id=3&from=2022-01-06&to=2022-01-07&page=2&limit=5&order=desc", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetAllBadParamInboudOrders(t *testing.T) {
+	repo := new(dbIOMock)
+	r := createServiceIO(NewInboudOrders(inboudOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/inboundOrders/?page=first", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetNonExistentInboudOrders(t *testing.T) {
+	repo := new(dbIOMock)
+	repo.On("Get", mock.Anything, 9).Return(domain.InboudOrders{}, sql.ErrNoRows)
+	r := createServiceIO(NewInboudOrders(inboudOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/inboundOrders/9", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestCreateWithoutQuantityInboudOrders(t *testing.T) {
+	orderDate, _ := time.Parse("2006-01-02", "2022-01-06")
+	order := domain.InboudOrders{OrderDate: orderDate, OrderNumber: "12312", EmployeeId: 1, ProductBatchId: 2, WarehouseID: 3}
+	saved := order
+	saved.ID = 1
+	saved.Quantity = 20
+	repo := new(dbIOMock)
+	repo.On("ExistsEmployee", mock.Anything, 1).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 2).Return(true)
+	repo.On("ExistsEmployeeInWarehouse", mock.Anything, 1, 3).Return(true)
+	repo.On("Exists", mock.Anything, "12312").Return(false)
+	repo.On("Save", mock.Anything, order).Return(1, nil)
+	repo.On("Get", mock.Anything, 1).Return(saved, nil)
+	r := createServiceIO(NewInboudOrders(inboudOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/inboundOrders/", `{
+		"order_date": "2022-01-06",
+		"order_number": "12312",
+		"employee_id": 1,
+		"product_batch_id": 2,
+		"warehouse_id": 3
+		}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var objRes struct {
+		Data domain.InboudOrders
+	}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &objRes))
+	assert.Equal(t, 20, objRes.Data.Quantity)
+}
+
+func TestCreateNegativeQuantityInboudOrders(t *testing.T) {
+	repo := new(dbIOMock)
+	r := createServiceIO(NewInboudOrders(inboudOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/inboundOrders/", `{
+		"order_date": "2022-01-06",
+		"order_number": "12312",
+		"employee_id": 1,
+		"product_batch_id": 2,
+		"warehouse_id": 3,
+		"quantity": -10
+		}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
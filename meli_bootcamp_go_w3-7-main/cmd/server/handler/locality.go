@@ -2,7 +2,12 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/locality"
@@ -16,16 +21,308 @@ type Locality struct {
 
 const tokenC string = "1234"
 
+// maxLocalityImportSize caps the body accepted by the locality import.
+const maxLocalityImportSize = 10 << 20
+
 func NewLocality(p locality.Service) *Locality {
 	return &Locality{
 		service: p,
 	}
 }
+
+// validToken rejects the request with 401 unless it carries the locality token.
+func validToken(c *gin.Context) bool {
+	if c.Request.Header.Get("token") != tokenC {
+		c.JSON(401, web.NewResponse(401, nil, "Token inválido"))
+		return false
+	}
+	return true
+}
+
+// ListLocalities godoc
+// @Summary List localities
+// @Tag Localities
+// @Description get all localities with their province and country
+// @Accept json
+// @Produce json
+// @Param        token  header  string  true  "token"
+// @Success 200 {object} web.response
+// @Failure 401 {object} web.errorResponse
+// @Router /api/v1/localities [get]
+func (s *Locality) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !validToken(c) {
+			return
+		}
+		localities, err := s.service.GetAll(c.Request.Context())
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, localities)
+	}
+}
+
+// GetLocality godoc
+// @Summary      Get Locality
+// @Description  get Locality by ID
+// @Tags         localities
+// @Accept       json
+// @Produce      json
+// @Param        token  header  string  true  "token"
+// @Param        id     path    int     true  "Locality ID"
+// @Success      200  {object}  web.response
+// @Failure      401  {object}  web.errorResponse
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/localities/{id} [get]
+func (s *Locality) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !validToken(c) {
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		l, err := s.service.Get(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, locality.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el locality con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, l)
+	}
+}
+
+// UpdateLocality godoc
+// @Summary Update locality
+// @Tag Localities
+// @Description update the fields sent of a locality
+// @Accept json
+// @Produce json
+// @Param        token  header  string  true  "token"
+// @Param        id     path    int     true  "Locality ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/localities/{id} [patch]
+func (s *Locality) Update() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !validToken(c) {
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		req := domain.Locality{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "Error en los datos de la petición: %s", err)
+			return
+		}
+		ctx := c.Request.Context()
+		last, err := s.service.Get(ctx, id)
+		if err != nil {
+			if errors.Is(err, locality.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el locality con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		l, err := s.service.Update(ctx, updateLocalityFields(last, req))
+		if err != nil {
+			switch {
+			case errors.Is(err, locality.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe el locality con el id %d", id)
+			case errors.Is(err, locality.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, "%s", "Ya existe un locality con ese ZipCode")
+			case errors.Is(err, locality.ErrCountryNotFound), errors.Is(err, locality.ErrProvinceNotFound):
+				web.Error(c, http.StatusConflict, "Error: %s", err.Error())
+			case errors.Is(err, locality.ErrProvinceCountryMismatch):
+				web.Error(c, http.StatusUnprocessableEntity, "Error: %s", err.Error())
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			}
+			return
+		}
+		web.Success(c, http.StatusOK, l)
+	}
+}
+
+// DeleteLocality godoc
+// @Summary Delete a locality
+// @Tag Localities
+// @Description delete a locality without sellers or carries
+// @Accept json
+// @Produce json
+// @Param        token  header  string  true  "token"
+// @Param        id     path    int     true  "Locality ID"
+// @Success      204  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Failure      409  {object}  web.errorResponse
+// @Router       /api/v1/localities/{id} [delete]
+func (s *Locality) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !validToken(c) {
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		err = s.service.Delete(c.Request.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, locality.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe el locality con el id %d", id)
+			case errors.Is(err, locality.ErrHasDependents):
+				web.Error(c, http.StatusConflict, "%s", "El locality tiene sellers o carries asociados")
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			}
+			return
+		}
+		web.Success(c, http.StatusNoContent, "")
+	}
+}
+
+// LocalityReport godoc
+// @Summary Locality report
+// @Tag Localities
+// @Description get the number of sellers and carries of a locality
+// @Accept json
+// @Produce json
+// @Param        token  header  string  true  "token"
+// @Param        id     path    int     true  "Locality ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/localities/{id}/report [get]
+func (s *Locality) GetReport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !validToken(c) {
+			return
+		}
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		report, err := s.service.GetReport(c.Request.Context(), id)
+		if err != nil {
+			if errors.Is(err, locality.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el locality con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, report)
+	}
+}
+
+// ImportLocalities godoc
+// @Summary Import localities
+// @Tag Localities
+// @Description create localities from a CSV (text/csv) or NDJSON (application/x-ndjson) body, or from the file field of a multipart/form-data upload, skipping zip codes already stored. Each row has zip_code, locality, province and country
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Param        token  header    string  true   "token"
+// @Param        file   formData  file    false  "CSV or NDJSON file, for multipart/form-data uploads"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Failure 415 {object} web.errorResponse
+// @Router /api/v1/localities/import [post]
+func (s *Locality) Import() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !validToken(c) {
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxLocalityImportSize)
+		var body io.Reader = c.Request.Body
+		format := localityImportFormat(c.ContentType())
+		if c.ContentType() == "multipart/form-data" {
+			fileHeader, err := c.FormFile("file")
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "El archivo debe enviarse en el campo file: %s", err.Error())
+				return
+			}
+			format = localityImportFormat(fileHeader.Header.Get("Content-Type"))
+			if format == "" {
+				switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+				case ".csv":
+					format = locality.FormatCSV
+				case ".ndjson", ".jsonl":
+					format = locality.FormatNDJSON
+				}
+			}
+			file, err := fileHeader.Open()
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "Error: %s", err.Error())
+				return
+			}
+			defer file.Close()
+			body = file
+		}
+		if format == "" {
+			web.Error(c, http.StatusUnsupportedMediaType, "%s", "El Content-Type debe ser text/csv, application/x-ndjson o multipart/form-data con un archivo .csv o .ndjson")
+			return
+		}
+		report, err := s.service.Import(c.Request.Context(), format, body)
+		if err != nil {
+			if errors.Is(err, locality.ErrInvalidFormat) {
+				web.Error(c, http.StatusBadRequest, "Error: %s", err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, report)
+	}
+}
+
+// localityImportFormat maps the media type of an import file to its format, or
+// returns "" when it is not supported.
+func localityImportFormat(mediaType string) string {
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "text/csv":
+		return locality.FormatCSV
+	case "application/x-ndjson", "application/ndjson":
+		return locality.FormatNDJSON
+	}
+	return ""
+}
+
+func updateLocalityFields(last domain.Locality, req domain.Locality) domain.Locality {
+	if req.ZipCode != "" {
+		last.ZipCode = req.ZipCode
+	}
+	if req.LocalityName != "" {
+		last.LocalityName = req.LocalityName
+	}
+	if req.ProvinceID != 0 {
+		last.ProvinceID = req.ProvinceID
+	}
+	if req.CountryID != 0 {
+		last.CountryID = req.CountryID
+	}
+	return last
+}
+
 func (s *Locality) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.Request.Header.Get("token")
-		if token != tokenC {
-			c.JSON(401, web.NewResponse(401, nil, "Token inválido"))
+		if !validToken(c) {
 			return
 		}
 		var req domain.Locality
@@ -45,17 +342,28 @@ func (s *Locality) Create() gin.HandlerFunc {
 			c.JSON(422, web.NewResponse(422, nil, "El nombre de la  localidad es requerido"))
 			return
 		}
-		if req.ProvinceName == "" {
-			c.JSON(422, web.NewResponse(422, nil, "El nombre de la provincia es requerido"))
+		if req.ProvinceID == 0 {
+			c.JSON(422, web.NewResponse(422, nil, "El province_id es requerido"))
 			return
 		}
-		if req.CountryName == "" {
-			c.JSON(422, web.NewResponse(422, nil, "El nombre del pais es requerido"))
+		if req.CountryID == 0 {
+			c.JSON(422, web.NewResponse(422, nil, "El country_id es requerido"))
 			return
 		}
 		p, err := s.service.Save(c.Request.Context(), req)
 		if err != nil {
-			c.JSON(422, web.NewResponse(422, nil, err.Error()))
+			switch {
+			case errors.Is(err, locality.ErrAlreadyExists):
+				web.Error(c, 409, "%s", "Ya existe un locality con ese ZipCode")
+			case errors.Is(err, locality.ErrCountryNotFound):
+				web.Error(c, 409, "No existe el country con el id %d", req.CountryID)
+			case errors.Is(err, locality.ErrProvinceNotFound):
+				web.Error(c, 409, "No existe la province con el id %d", req.ProvinceID)
+			case errors.Is(err, locality.ErrProvinceCountryMismatch):
+				web.Error(c, 422, "La province %d no pertenece al country %d", req.ProvinceID, req.CountryID)
+			default:
+				c.JSON(422, web.NewResponse(422, nil, err.Error()))
+			}
 			return
 		}
 		c.JSON(201, web.NewResponse(201, p, ""))
@@ -63,9 +371,7 @@ func (s *Locality) Create() gin.HandlerFunc {
 }
 func (s *Locality) Get() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.Request.Header.Get("token")
-		if token != tokenC {
-			c.JSON(401, web.NewResponse(401, nil, "Token inválido"))
+		if !validToken(c) {
 			return
 		}
 		zipCode := c.Request.URL.Query().Get("zip_code")
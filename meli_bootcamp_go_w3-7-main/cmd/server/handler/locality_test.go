@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/locality"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type localityServiceMock struct {
+	locality.Service
+	mock.Mock
+}
+
+func (m *localityServiceMock) Import(ctx context.Context, format string, r io.Reader) (domain.LocalityImportReport, error) {
+	data, _ := io.ReadAll(r)
+	args := m.Called(ctx, format, string(data))
+	return args.Get(0).(domain.LocalityImportReport), args.Error(1)
+}
+
+func createServerLocalityImport(s locality.Service) *gin.Engine {
+	r := gin.Default()
+	r.POST("/api/v1/localities/import", NewLocality(s).Import())
+	return r
+}
+
+func createMultipartLocalityImport(t *testing.T, filename, contentType, content string) *http.Request {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	header := make(map[string][]string)
+	header["Content-Disposition"] = []string{`form-data; name="file"; filename="` + filename + `"`}
+	if contentType != "" {
+		header["Content-Type"] = []string{contentType}
+	}
+	part, err := w.CreatePart(header)
+	assert.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/localities/import", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("token", tokenC)
+	return req
+}
+
+func TestImportLocalitiesMultipartCSV(t *testing.T) {
+	content := "zip_code,locality,province,country\n1000,Palermo,Buenos Aires,Argentina\n"
+	s := new(localityServiceMock)
+	s.On("Import", mock.Anything, locality.FormatCSV, content).Return(domain.LocalityImportReport{Created: 1}, nil)
+	rr := httptest.NewRecorder()
+	createServerLocalityImport(s).ServeHTTP(rr, createMultipartLocalityImport(t, "localities.csv", "application/octet-stream", content))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	s.AssertExpectations(t)
+}
+
+func TestImportLocalitiesMultipartNDJSONContentType(t *testing.T) {
+	content := `{"zip_code":"1000","locality":"Palermo","province":"Buenos Aires","country":"Argentina"}` + "\n"
+	s := new(localityServiceMock)
+	s.On("Import", mock.Anything, locality.FormatNDJSON, content).Return(domain.LocalityImportReport{Created: 1}, nil)
+	rr := httptest.NewRecorder()
+	createServerLocalityImport(s).ServeHTTP(rr, createMultipartLocalityImport(t, "localities", "application/x-ndjson", content))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	s.AssertExpectations(t)
+}
+
+func TestImportLocalitiesMultipartUnsupportedFile(t *testing.T) {
+	s := new(localityServiceMock)
+	rr := httptest.NewRecorder()
+	createServerLocalityImport(s).ServeHTTP(rr, createMultipartLocalityImport(t, "localities.xlsx", "", "x"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	s.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestImportLocalitiesMultipartMissingFile(t *testing.T) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	assert.NoError(t, w.WriteField("other", "x"))
+	assert.NoError(t, w.Close())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/localities/import", &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("token", tokenC)
+	rr := httptest.NewRecorder()
+	createServerLocalityImport(new(localityServiceMock)).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestImportLocalitiesRawCSV(t *testing.T) {
+	content := "zip_code,locality,province,country\n1000,Palermo,Buenos Aires,Argentina\n"
+	s := new(localityServiceMock)
+	s.On("Import", mock.Anything, locality.FormatCSV, content).Return(domain.LocalityImportReport{Created: 1}, nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/localities/import", bytes.NewBufferString(content))
+	req.Header.Set("Content-Type", "text/csv")
+	req.Header.Set("token", tokenC)
+	rr := httptest.NewRecorder()
+	createServerLocalityImport(s).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	s.AssertExpectations(t)
+}
@@ -66,7 +66,7 @@ func (p *Product) Create() gin.HandlerFunc {
 		}
 		savedProduct, err := p.productService.Save(c, productToSave)
 		if err != nil {
-			if err == product.ErrAlreadyExists {
+			if err == product.ErrAlreadyExists || err == product.ErrProductTypeNotFound {
 				web.Error(c, http.StatusConflict, "error: %s", err.Error())
 				return
 			}
@@ -102,6 +102,10 @@ func (p *Product) Update() gin.HandlerFunc {
 				web.Error(c, http.StatusNotFound, "error: %s", err.Error())
 				return
 			}
+			if err == product.ErrProductTypeNotFound {
+				web.Error(c, http.StatusConflict, "error: %s", err.Error())
+				return
+			}
 			web.Error(c, http.StatusInternalServerError, "error: %s", err.Error())
 			return
 		}
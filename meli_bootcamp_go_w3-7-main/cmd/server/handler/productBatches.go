@@ -1,11 +1,13 @@
 package handler
 
 import (
-	//"context"
-	//"net/http"
-	//"context"
-	//"strconv"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
 
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/alerts"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/productBatches"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
@@ -13,12 +15,14 @@ import (
 )
 
 type ProductBatches struct {
-	service productBatches.Service
+	service       productBatches.Service
+	alertsService alerts.Service
 }
 
-func NewProductBatches(p productBatches.Service) *ProductBatches {
+func NewProductBatches(p productBatches.Service, a alerts.Service) *ProductBatches {
 	return &ProductBatches{
-		service: p,
+		service:       p,
+		alertsService: a,
 	}
 }
 
@@ -76,42 +80,157 @@ func (s *ProductBatches) CreateProductBatches() gin.HandlerFunc {
 
 		p, err := s.service.Save(c.Request.Context(), req)
 		if err != nil {
+			switch {
+			case errors.Is(err, productBatches.ErrTemperatureOutOfRange):
+				web.Error(c, http.StatusUnprocessableEntity, "%s", err.Error())
+				return
+			case errors.Is(err, productBatches.ErrSectionNotFound),
+				errors.Is(err, productBatches.ErrProductNotFound),
+				errors.Is(err, productBatches.ErrSectionCapacityExceeded),
+				errors.Is(err, productBatches.ErrProductTypeMismatch):
+				web.Error(c, http.StatusConflict, "%s", err.Error())
+				return
+			}
 			c.JSON(422, web.NewResponse(422, nil, err.Error()))
 			return
 		}
+		if _, err := s.alertsService.EvaluateSection(c.Request.Context(), p.SectionId); err != nil {
+			log.Printf("evaluating alerts of section %d: %v", p.SectionId, err)
+		}
 		c.JSON(201, web.NewResponse(201, p, ""))
 	}
 }
 
-/* func (s *ProductBatches) GetProductBatches() gin.HandlerFunc {
+// ReportProducts godoc
+// @Summary Report products by section
+// @Tag ProductBatches
+// @Description get the sum of current_quantity of the product batches in every section, or in the section given by id
+// @Accept json
+// @Produce json
+// @Param id query int false "Section ID"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/sections/reportProducts [get]
+func (s *ProductBatches) GetProductBatches() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		id := c.Request.URL.Query().Get("id")
-		if id == "" {
-			web.Error(c, 404, "%s", "No hay productos batches")
+		idurl := c.Query("id")
+		ctx := context.Background()
+		if idurl == "" {
+			reports, err := s.service.GetAllReportProducts(ctx)
+			if err != nil {
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+			web.Success(c, http.StatusOK, reports)
 			return
 		}
 
-		idParse, errorParse := strconv.Atoi(id)
-		if errorParse != nil {
-			web.Error(c, 404, "%s", "no se pudo parsear")
+		id, err := strconv.ParseInt(idurl, 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
 			return
 		}
-		ctx := context.Background()
-		serv, err := s.service.GetQuantity(ctx, idParse)
+		report, err := s.service.GetReportBySectionIdProducts(ctx, int(id))
 		if err != nil {
-			web.Error(c, 404, "%s", "No hay productos batches con id")
+			if errors.Is(err, productBatches.ErrSectionNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe la seccion con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
 			return
 		}
-		if serv.ID == 0 {
-			web.Error(c, 404, "%s", "No hay productos batches VACIO")
+		web.Success(c, http.StatusOK, report)
+	}
+}
+
+// PickProductBatches godoc
+// @Summary Pick product stock
+// @Tag ProductBatches
+// @Description take a quantity of a product from its batches, earliest due date first, and return the pick list
+// @Accept json
+// @Produce json
+// @Success 200 {object} web.response
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/productBatches/pick [post]
+func (s *ProductBatches) Pick() gin.HandlerFunc {
+	type request struct {
+		ProductID         int    `json:"product_id" binding:"required"`
+		Quantity          int    `json:"quantity" binding:"required"`
+		WarehouseID       int    `json:"warehouse_id"`
+		EmployeeID        int    `json:"employee_id"`
+		ReferenceDocument string `json:"reference_document"`
+	}
+	return func(c *gin.Context) {
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "Los campos product_id y quantity son requeridos")
+			return
+		}
+		pickList, err := s.service.Pick(c.Request.Context(), domain.PickRequest{
+			ProductID:         req.ProductID,
+			Quantity:          req.Quantity,
+			WarehouseID:       req.WarehouseID,
+			EmployeeID:        req.EmployeeID,
+			ReferenceDocument: req.ReferenceDocument,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, productBatches.ErrInvalidQuantity):
+				web.Error(c, http.StatusUnprocessableEntity, "%s", err.Error())
+				return
+			case errors.Is(err, productBatches.ErrInsufficientStock):
+				web.Error(c, http.StatusConflict, "%s", err.Error())
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusOK, pickList)
+	}
+}
+
+// ExpiringProductBatches godoc
+// @Summary Near due product batches
+// @Tag ProductBatches
+// @Description list the batches with stock due within the next days, grouped by warehouse and section, plus the already expired ones the expiry job has not written off yet
+// @Produce json
+// @Param days         query int false "Days ahead to look for (default 7)"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Router /api/v1/productBatches/expiring [get]
+func (s *ProductBatches) GetExpiring() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		days := 7
+		if c.Query("days") != "" {
+			parsed, err := strconv.Atoi(c.Query("days"))
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "Error: %s", "invalid days")
+				return
+			}
+			days = parsed
+		}
+		warehouseId := 0
+		if c.Query("warehouse_id") != "" {
+			parsed, err := strconv.Atoi(c.Query("warehouse_id"))
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "Error: %s", "invalid warehouse_id")
+				return
+			}
+			warehouseId = parsed
+		}
+		report, err := s.service.GetExpiring(c.Request.Context(), days, warehouseId)
+		if err != nil {
+			if errors.Is(err, productBatches.ErrInvalidDays) {
+				web.Error(c, http.StatusBadRequest, "%s", err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
 			return
 		}
-		type ProductBatchesData struct {
-			SectionID     int `json:"section_id"`
-			SectionNumber int `json:"section_number"`
-			ProductsCount int `json:"products_count"`
-		}
-		var data ProductBatchesData
-		sections, errorSec := s.service.GetBySectionNumber(ctx, serv.SectionId)
+		web.Success(c, http.StatusOK, report)
 	}
-} */
+}
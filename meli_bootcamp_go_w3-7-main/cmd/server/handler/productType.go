@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/productType"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type ProductType struct {
+	productTypeService productType.Service
+}
+
+func NewProductType(p productType.Service) *ProductType {
+	return &ProductType{
+		productTypeService: p,
+	}
+}
+
+// ListProductTypes godoc
+// @Summary List product types
+// @Tag ProductTypes
+// @Description get all product types
+// @Accept json
+// @Produce json
+// @Success 200 {object} web.response
+// @Router /api/v1/productTypes [get]
+func (p *ProductType) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		productTypes, err := p.productTypeService.GetAll(ctx)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, productTypes)
+	}
+}
+
+// GetProductType godoc
+// @Summary      Get Product Type
+// @Description  get Product Type by ID
+// @Tags         productTypes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Product Type ID"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/productTypes/{id} [get]
+func (p *ProductType) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		pt, err := p.productTypeService.Get(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, productType.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el product type con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, pt)
+	}
+}
+
+// CreateProductType godoc
+// @Summary Create product type
+// @Tag ProductTypes
+// @Description create a new product type
+// @Accept json
+// @Produce json
+// @Success 201 {object} web.response
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/productTypes [post]
+func (p *ProductType) Create() gin.HandlerFunc {
+	type request struct {
+		Description string `json:"description" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo description es requerido")
+			return
+		}
+		ctx := context.Background()
+		pt, err := p.productTypeService.Save(ctx, domain.ProductType{Description: req.Description})
+		if err != nil {
+			if errors.Is(err, productType.ErrAlreadyExists) {
+				web.Error(c, http.StatusConflict, "%s", "Ya existe un product type con esa description")
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusCreated, pt)
+	}
+}
+
+// UpdateProductType godoc
+// @Summary Update product type
+// @Tag ProductTypes
+// @Description update the description of a product type
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Product Type ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Failure 409 {object} web.errorResponse
+// @Router /api/v1/productTypes/{id} [patch]
+func (p *ProductType) Update() gin.HandlerFunc {
+	type request struct {
+		Description string `json:"description" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo description es requerido")
+			return
+		}
+		ctx := context.Background()
+		pt, err := p.productTypeService.Update(ctx, domain.ProductType{ID: int(id), Description: req.Description})
+		if err != nil {
+			switch {
+			case errors.Is(err, productType.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe el product type con el id %d", id)
+				return
+			case errors.Is(err, productType.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, "%s", "Ya existe un product type con esa description")
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusOK, pt)
+	}
+}
+
+// DeleteProductType godoc
+// @Summary Delete a product type
+// @Tag ProductTypes
+// @Description delete a product type not used by any product or section
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Product Type ID"
+// @Success      204  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Failure      409  {object}  web.errorResponse
+// @Router       /api/v1/productTypes/{id} [delete]
+func (p *ProductType) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		err = p.productTypeService.Delete(ctx, int(id))
+		if err != nil {
+			switch {
+			case errors.Is(err, productType.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe el product type con el id %d", id)
+				return
+			case errors.Is(err, productType.ErrInUse):
+				web.Error(c, http.StatusConflict, "%s", "El product type tiene products o sections asociados")
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusNoContent, "")
+	}
+}
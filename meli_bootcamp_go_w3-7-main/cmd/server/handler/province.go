@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/province"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Province struct {
+	provinceService province.Service
+}
+
+func NewProvince(p province.Service) *Province {
+	return &Province{
+		provinceService: p,
+	}
+}
+
+// ListProvinces godoc
+// @Summary List provinces
+// @Tag Provinces
+// @Description get all provinces, optionally filtered by country
+// @Accept json
+// @Produce json
+// @Param        country_id  query  int  false  "Country ID"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Router /api/v1/provinces [get]
+func (p *Province) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		countryId := 0
+		if value := c.Query("country_id"); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "Error: invalid %s", "country_id")
+				return
+			}
+			countryId = id
+		}
+		ctx := context.Background()
+		provinces, err := p.provinceService.GetAll(ctx, countryId)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, provinces)
+	}
+}
+
+// GetProvince godoc
+// @Summary      Get Province
+// @Description  get Province by ID
+// @Tags         provinces
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Province ID"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/provinces/{id} [get]
+func (p *Province) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		result, err := p.provinceService.Get(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, province.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe la province con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// CreateProvince godoc
+// @Summary Create province
+// @Tag Provinces
+// @Description create a new province in a country
+// @Accept json
+// @Produce json
+// @Success 201 {object} web.response
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/provinces [post]
+func (p *Province) Create() gin.HandlerFunc {
+	type request struct {
+		ProvinceName string `json:"province_name" binding:"required"`
+		CountryID    int    `json:"country_id" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "Los campos province_name y country_id son requeridos")
+			return
+		}
+		ctx := context.Background()
+		result, err := p.provinceService.Save(ctx, domain.Province{ProvinceName: req.ProvinceName, CountryID: req.CountryID})
+		if err != nil {
+			switch {
+			case errors.Is(err, province.ErrCountryNotFound):
+				web.Error(c, http.StatusConflict, "No existe el country con el id %d", req.CountryID)
+				return
+			case errors.Is(err, province.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, "%s", "Ya existe una province con ese nombre en el country")
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusCreated, result)
+	}
+}
+
+// UpdateProvince godoc
+// @Summary Update province
+// @Tag Provinces
+// @Description update the name and country of a province
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Province ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Failure 409 {object} web.errorResponse
+// @Router /api/v1/provinces/{id} [patch]
+func (p *Province) Update() gin.HandlerFunc {
+	type request struct {
+		ProvinceName string `json:"province_name" binding:"required"`
+		CountryID    int    `json:"country_id" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "Los campos province_name y country_id son requeridos")
+			return
+		}
+		ctx := context.Background()
+		result, err := p.provinceService.Update(ctx, domain.Province{ID: int(id), ProvinceName: req.ProvinceName, CountryID: req.CountryID})
+		if err != nil {
+			switch {
+			case errors.Is(err, province.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe la province con el id %d", id)
+				return
+			case errors.Is(err, province.ErrCountryNotFound):
+				web.Error(c, http.StatusConflict, "No existe el country con el id %d", req.CountryID)
+				return
+			case errors.Is(err, province.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, "%s", "Ya existe una province con ese nombre en el country")
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusOK, result)
+	}
+}
+
+// DeleteProvince godoc
+// @Summary Delete a province
+// @Tag Provinces
+// @Description delete a province without localities
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Province ID"
+// @Success      204  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Failure      409  {object}  web.errorResponse
+// @Router       /api/v1/provinces/{id} [delete]
+func (p *Province) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		err = p.provinceService.Delete(ctx, int(id))
+		if err != nil {
+			switch {
+			case errors.Is(err, province.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe la province con el id %d", id)
+				return
+			case errors.Is(err, province.ErrHasLocalities):
+				web.Error(c, http.StatusConflict, "%s", "La province tiene localities asociadas")
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusNoContent, "")
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/purchaseOrders"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseOrders struct {
+	purchaseOrdersService purchaseOrders.Service
+}
+
+func NewPurchaseOrders(p purchaseOrders.Service) *PurchaseOrders {
+	return &PurchaseOrders{
+		purchaseOrdersService: p,
+	}
+}
+
+// ListPurchaseOrders godoc
+// @Summary List purchase orders
+// @Tag PurchaseOrders
+// @Description get all purchase orders
+// @Accept json
+// @Produce json
+// @Success 200 {object} web.response
+// @Router /api/v1/purchaseOrders [get]
+func (p *PurchaseOrders) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := context.Background()
+		orders, err := p.purchaseOrdersService.GetAll(ctx)
+		if err != nil {
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, orders)
+	}
+}
+
+// GetPurchaseOrder godoc
+// @Summary      Get Purchase Order
+// @Description  get Purchase Order by ID
+// @Tags         purchaseOrders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Purchase Order ID"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/purchaseOrders/{id} [get]
+func (p *PurchaseOrders) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		ctx := context.Background()
+		order, err := p.purchaseOrdersService.Get(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, purchaseOrders.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe la purchase order con el id %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, order)
+	}
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Create purchase order
+// @Tag PurchaseOrders
+// @Description create a new purchase order for an existing buyer and product record
+// @Accept json
+// @Produce json
+// @Success 201 {object} web.response
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/purchaseOrders [post]
+func (p *PurchaseOrders) Create() gin.HandlerFunc {
+	type request struct {
+		OrderNumber     string `json:"order_number" binding:"required"`
+		OrderDate       string `json:"order_date" binding:"required"`
+		TrackingCode    string `json:"tracking_code" binding:"required"`
+		BuyerID         int    `json:"buyer_id" binding:"required"`
+		ProductRecordID int    `json:"product_record_id" binding:"required"`
+		OrderStatusID   int    `json:"order_status_id" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "Todos los campos son requeridos")
+			return
+		}
+		orderDate, err := time.Parse("2006-01-02", req.OrderDate)
+		if err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo order_date debe tener el formato YYYY-MM-DD")
+			return
+		}
+		ctx := context.Background()
+		order, err := p.purchaseOrdersService.Save(ctx, domain.PurchaseOrders{
+			OrderNumber:     req.OrderNumber,
+			OrderDate:       orderDate,
+			TrackingCode:    req.TrackingCode,
+			BuyerID:         req.BuyerID,
+			ProductRecordID: req.ProductRecordID,
+			OrderStatusID:   req.OrderStatusID,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, purchaseOrders.ErrBuyerNotFound),
+				errors.Is(err, purchaseOrders.ErrProductRecordNotFound),
+				errors.Is(err, purchaseOrders.ErrOrderStatusNotFound),
+				errors.Is(err, purchaseOrders.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, "%s", err)
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err)
+				return
+			}
+		}
+		web.Success(c, http.StatusCreated, order)
+	}
+}
+
+// UpdatePurchaseOrderStatus godoc
+// @Summary Update purchase order status
+// @Tag PurchaseOrders
+// @Description move a purchase order to a new status, rejecting illegal transitions
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Purchase Order ID"
+// @Success 200 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Failure 409 {object} web.errorResponse
+// @Router /api/v1/purchaseOrders/{id}/status [patch]
+func (p *PurchaseOrders) UpdateStatus() gin.HandlerFunc {
+	type request struct {
+		OrderStatusID int `json:"order_status_id" binding:"required"`
+	}
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo order_status_id es requerido")
+			return
+		}
+		ctx := context.Background()
+		order, err := p.purchaseOrdersService.UpdateStatus(ctx, int(id), req.OrderStatusID)
+		if err != nil {
+			switch {
+			case errors.Is(err, purchaseOrders.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe la purchase order con el id %d", id)
+				return
+			case errors.Is(err, purchaseOrders.ErrOrderStatusNotFound),
+				errors.Is(err, purchaseOrders.ErrInvalidStatusTransition):
+				web.Error(c, http.StatusConflict, "%s", err)
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err)
+				return
+			}
+		}
+		web.Success(c, http.StatusOK, order)
+	}
+}
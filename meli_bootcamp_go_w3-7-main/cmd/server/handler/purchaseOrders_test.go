@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/purchaseOrders"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type dbPOMock struct {
+	mock.Mock
+}
+
+func (m *dbPOMock) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *dbPOMock) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *dbPOMock) Exists(ctx context.Context, orderNumber string) bool {
+	args := m.Called(ctx, orderNumber)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) Save(ctx context.Context, p domain.PurchaseOrders) (int, error) {
+	args := m.Called(ctx, p)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *dbPOMock) ExistsBuyer(ctx context.Context, buyerId int) bool {
+	args := m.Called(ctx, buyerId)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) ExistsProductRecord(ctx context.Context, productRecordId int) bool {
+	args := m.Called(ctx, productRecordId)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) ExistsOrderStatus(ctx context.Context, orderStatusId int) bool {
+	args := m.Called(ctx, orderStatusId)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) UpdateStatus(ctx context.Context, id int, fromStatusId int, orderStatusId int) error {
+	args := m.Called(ctx, id, fromStatusId, orderStatusId)
+	return args.Error(0)
+}
+
+func createServicePO(p *PurchaseOrders) *gin.Engine {
+	r := gin.Default()
+	pr := r.Group("api/v1/purchaseOrders")
+	{
+		pr.GET("/", p.GetAll())
+		pr.GET("/:id", p.Get())
+		pr.POST("/", p.Create())
+		pr.PATCH("/:id/status", p.UpdateStatus())
+	}
+	return r
+}
+
+const purchaseOrderBody = `{
+	"order_number": "order#1",
+	"order_date": "2022-01-06",
+	"tracking_code": "abscf123",
+	"buyer_id": 1,
+	"product_record_id": 1,
+	"order_status_id": 1
+	}`
+
+func TestCreateOkPurchaseOrders(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("ExistsBuyer", mock.Anything, 1).Return(true)
+	repo.On("ExistsProductRecord", mock.Anything, 1).Return(true)
+	repo.On("ExistsOrderStatus", mock.Anything, 1).Return(true)
+	repo.On("Exists", mock.Anything, "order#1").Return(false)
+	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
+	r := createServicePO(NewPurchaseOrders(purchaseOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/purchaseOrders/", purchaseOrderBody)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestCreateFailBuyerPurchaseOrders(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("ExistsBuyer", mock.Anything, 1).Return(false)
+	r := createServicePO(NewPurchaseOrders(purchaseOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/purchaseOrders/", purchaseOrderBody)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestCreateFailFieldPurchaseOrders(t *testing.T) {
+	repo := new(dbPOMock)
+	r := createServicePO(NewPurchaseOrders(purchaseOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/purchaseOrders/", `{"order_number": "order#1"}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestGetNonExistentPurchaseOrders(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 3).Return(domain.PurchaseOrders{}, sql.ErrNoRows)
+	r := createServicePO(NewPurchaseOrders(purchaseOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/purchaseOrders/3", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestUpdateStatusConflictPurchaseOrders(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 1).Return(domain.PurchaseOrders{ID: 1, OrderStatusID: purchaseOrders.OrderStatusDelivered}, nil)
+	repo.On("ExistsOrderStatus", mock.Anything, purchaseOrders.OrderStatusPending).Return(true)
+	r := createServicePO(NewPurchaseOrders(purchaseOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodPatch, "/api/v1/purchaseOrders/1/status", `{"order_status_id": 1}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestUpdateStatusOkPurchaseOrders(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 1).Return(domain.PurchaseOrders{ID: 1, OrderStatusID: purchaseOrders.OrderStatusPending}, nil)
+	repo.On("ExistsOrderStatus", mock.Anything, purchaseOrders.OrderStatusPicking).Return(true)
+	repo.On("UpdateStatus", mock.Anything, 1, purchaseOrders.OrderStatusPending, purchaseOrders.OrderStatusPicking).Return(nil)
+	r := createServicePO(NewPurchaseOrders(purchaseOrders.NewService(repo)))
+	req, rr := createRequestInboudOrdersTest(http.MethodPatch, "/api/v1/purchaseOrders/1/status", `{"order_status_id": 2}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -2,9 +2,12 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"strconv"
 
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/alerts"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/section"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
@@ -13,11 +16,13 @@ import (
 
 type Section struct {
 	sectionService section.Service
+	alertsService  alerts.Service
 }
 
-func NewSection(s section.Service) *Section {
+func NewSection(s section.Service, a alerts.Service) *Section {
 	return &Section{
 		sectionService: s,
+		alertsService:  a,
 	}
 }
 
@@ -68,8 +73,8 @@ func (s *Section) Create() gin.HandlerFunc {
 		}
 		ctx := context.Background()
 		fmt.Println("soy el request ", req)
-		if s.sectionService.Exists(ctx, req.SectionNumber) {
-			web.Error(c, 409, "%s", "Ya existe una Seccion con ese numero")
+		if s.sectionService.Exists(ctx, req.WarehouseID, req.SectionNumber) {
+			web.Error(c, 409, "%s", "Ya existe una Seccion con ese numero en el warehouse")
 			return
 		}
 		if req.SectionNumber == 0 {
@@ -98,6 +103,10 @@ func (s *Section) Create() gin.HandlerFunc {
 		}
 		sectionCreate, err := s.sectionService.Save(ctx, req)
 		if err != nil {
+			if errors.Is(err, section.ErrProductTypeNotFound) || errors.Is(err, section.ErrWarehouseNotFound) || errors.Is(err, section.ErrAlreadyExists) {
+				web.Error(c, 409, "Error: %s", err.Error())
+				return
+			}
 			web.Error(c, 404, "Error: %s", err.Error())
 			return
 		}
@@ -106,6 +115,13 @@ func (s *Section) Create() gin.HandlerFunc {
 	}
 }
 
+// sectionUpdateRequest carries the employee that changes current_capacity, which is
+// recorded in the stock movements ledger.
+type sectionUpdateRequest struct {
+	domain.Section
+	EmployeeID int `json:"employee_id"`
+}
+
 func (s *Section) Update() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -113,7 +129,7 @@ func (s *Section) Update() gin.HandlerFunc {
 			web.Error(c, 404, "Error: %s", "El ID no es valido")
 			return
 		}
-		var req domain.Section
+		var req sectionUpdateRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			web.Error(c, 404, "Error in code for: %s", err)
 			return
@@ -153,11 +169,22 @@ func (s *Section) Update() gin.HandlerFunc {
 			return
 		}
 		req.ID = int(id)
-		sectionUpdate, err := s.sectionService.Update(ctx, req)
+		sectionUpdate, err := s.sectionService.Update(ctx, req.Section, req.EmployeeID)
 		if err != nil {
+			if errors.Is(err, section.ErrTemperatureOutOfRange) || errors.Is(err, section.ErrEmployeeRequired) {
+				web.Error(c, 422, "Error: %s", err.Error())
+				return
+			}
+			if errors.Is(err, section.ErrProductTypeNotFound) || errors.Is(err, section.ErrWarehouseNotFound) || errors.Is(err, section.ErrAlreadyExists) || errors.Is(err, section.ErrEmployeeNotFound) {
+				web.Error(c, 409, "Error: %s", err.Error())
+				return
+			}
 			web.Error(c, 400, "Error: %s", err.Error())
 			return
 		}
+		if _, err := s.alertsService.EvaluateSection(ctx, sectionUpdate.ID); err != nil {
+			log.Printf("evaluating alerts of section %d: %v", sectionUpdate.ID, err)
+		}
 		web.Success(c, 200, sectionUpdate)
 	}
 }
@@ -12,11 +12,16 @@ import (
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func createSectionServer() *gin.Engine {
+	return createSectionServerWithAlerts(newAlertsServiceMockTR())
+}
+
+func createSectionServerWithAlerts(alertsService *AlertsServiceMock) *gin.Engine {
 	sectionService := NewSectionServiceMock()
-	section := NewSection(sectionService)
+	section := NewSection(sectionService, alertsService)
 	r := gin.Default()
 	sectionsGroup := r.Group("/sections")
 	{
@@ -116,7 +121,7 @@ func (s *SectionServiceMock) Save(ctx context.Context, se domain.Section) (domai
 	datos = append(datos, se)
 	return se, nil
 }
-func (s *SectionServiceMock) Update(ctx context.Context, se domain.Section) (domain.Section, error) {
+func (s *SectionServiceMock) Update(ctx context.Context, se domain.Section, employeeId int) (domain.Section, error) {
 	var datos []domain.Section
 	err := json.Unmarshal(s.Data, &datos)
 	if err != nil {
@@ -152,7 +157,7 @@ func (s *SectionServiceMock) Delete(ctx context.Context, id int) error {
 }
 
 //Sacarlo del service, asi no rompe por todos lados.
-func (s *SectionServiceMock) Exists(ctx context.Context, sectionNumber int) bool {
+func (s *SectionServiceMock) Exists(ctx context.Context, warehouseId, sectionNumber int) bool {
 	return false
 }
 
@@ -220,7 +225,8 @@ func Test_find_by_id_existent_section(t *testing.T) {
 }
 
 func Test_update_ok_section(t *testing.T) {
-	r := createSectionServer()
+	alertsService := newAlertsServiceMockTR()
+	r := createSectionServerWithAlerts(alertsService)
 	body := `
         {
 			"section_number": 41,
@@ -235,10 +241,12 @@ func Test_update_ok_section(t *testing.T) {
 	req, rr := createSectionRequestTest(http.MethodPatch, "/sections/1", body)
 	r.ServeHTTP(rr, req)
 	assert.Equal(t, 200, rr.Code)
+	alertsService.AssertCalled(t, "EvaluateSection", mock.Anything, 1)
 }
 
 func Test_update_non_existent_section(t *testing.T) {
-	r := createSectionServer()
+	alertsService := newAlertsServiceMockTR()
+	r := createSectionServerWithAlerts(alertsService)
 	body := `
         {
 			"section_number": 41,
@@ -253,6 +261,7 @@ func Test_update_non_existent_section(t *testing.T) {
 	req, rr := createSectionRequestTest(http.MethodPatch, "/sections/15", body)
 	r.ServeHTTP(rr, req)
 	assert.Equal(t, 400, rr.Code)
+	alertsService.AssertNotCalled(t, "EvaluateSection", mock.Anything, mock.Anything)
 }
 
 func Test_delete_non_existent_section(t *testing.T) {
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/shipments"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type Shipments struct {
+	shipmentsService shipments.Service
+}
+
+func NewShipments(s shipments.Service) *Shipments {
+	return &Shipments{
+		shipmentsService: s,
+	}
+}
+
+// GetShipment godoc
+// @Summary      Get Shipment
+// @Description  get a shipment and its tracking events by tracking code
+// @Tags         shipments
+// @Produce      json
+// @Param        tracking_code   path      string  true  "Tracking code"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Router       /api/v1/shipments/{tracking_code} [get]
+func (s *Shipments) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		trackingCode := c.Param("tracking_code")
+		ctx := context.Background()
+		shipment, err := s.shipmentsService.Get(ctx, trackingCode)
+		if err != nil {
+			if errors.Is(err, shipments.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No existe el shipment con el tracking code %s", trackingCode)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err)
+			return
+		}
+		web.Success(c, http.StatusOK, shipment)
+	}
+}
+
+// CreateShipment godoc
+// @Summary Create shipment
+// @Tag Shipments
+// @Description assign a carry to a purchase order or to a list of product batches and generate its tracking code
+// @Accept json
+// @Produce json
+// @Success 201 {object} web.response
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/shipments [post]
+func (s *Shipments) Create() gin.HandlerFunc {
+	type request struct {
+		CarryID         int   `json:"carry_id" binding:"required"`
+		PurchaseOrderID int   `json:"purchase_order_id"`
+		ProductBatchIDs []int `json:"product_batch_ids"`
+	}
+	return func(c *gin.Context) {
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo carry_id es requerido")
+			return
+		}
+		ctx := context.Background()
+		shipment, err := s.shipmentsService.Save(ctx, domain.Shipment{
+			CarryID:         req.CarryID,
+			PurchaseOrderID: req.PurchaseOrderID,
+			ProductBatchIDs: req.ProductBatchIDs,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, shipments.ErrInvalidContent):
+				web.Error(c, http.StatusUnprocessableEntity, "%s", err)
+				return
+			case errors.Is(err, shipments.ErrCarryNotFound),
+				errors.Is(err, shipments.ErrPurchaseOrderNotFound),
+				errors.Is(err, shipments.ErrPurchaseOrderNotReady),
+				errors.Is(err, shipments.ErrProductBatchNotFound),
+				errors.Is(err, shipments.ErrAlreadyExists):
+				web.Error(c, http.StatusConflict, "%s", err)
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err)
+				return
+			}
+		}
+		web.Success(c, http.StatusCreated, shipment)
+	}
+}
+
+// CreateShipmentEvent godoc
+// @Summary Create shipment event
+// @Tag Shipments
+// @Description record that a shipment was dispatched, passed a checkpoint (in-transit) or was delivered
+// @Accept json
+// @Produce json
+// @Param        tracking_code   path      string  true  "Tracking code"
+// @Success 201 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Failure 409 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/shipments/{tracking_code}/events [post]
+func (s *Shipments) CreateEvent() gin.HandlerFunc {
+	type request struct {
+		Type     string `json:"type" binding:"required"`
+		Location string `json:"location"`
+	}
+	return func(c *gin.Context) {
+		trackingCode := c.Param("tracking_code")
+		req := request{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El campo type es requerido")
+			return
+		}
+		ctx := context.Background()
+		event, err := s.shipmentsService.AddEvent(ctx, trackingCode, domain.ShipmentEvent{Type: req.Type, Location: req.Location})
+		if err != nil {
+			switch {
+			case errors.Is(err, shipments.ErrNotFound):
+				web.Error(c, http.StatusNotFound, "No existe el shipment con el tracking code %s", trackingCode)
+				return
+			case errors.Is(err, shipments.ErrInvalidEventType):
+				web.Error(c, http.StatusUnprocessableEntity, "%s", err)
+				return
+			case errors.Is(err, shipments.ErrInvalidTransition),
+				errors.Is(err, shipments.ErrPurchaseOrderNotReady):
+				web.Error(c, http.StatusConflict, "%s", err)
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err)
+				return
+			}
+		}
+		web.Success(c, http.StatusCreated, event)
+	}
+}
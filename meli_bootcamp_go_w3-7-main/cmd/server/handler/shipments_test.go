@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/shipments"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type ShipmentsServiceMock struct {
+	mock.Mock
+}
+
+func (m *ShipmentsServiceMock) Get(ctx context.Context, trackingCode string) (domain.Shipment, error) {
+	args := m.Called(ctx, trackingCode)
+	return args.Get(0).(domain.Shipment), args.Error(1)
+}
+
+func (m *ShipmentsServiceMock) Save(ctx context.Context, s domain.Shipment) (domain.Shipment, error) {
+	args := m.Called(ctx, s)
+	return args.Get(0).(domain.Shipment), args.Error(1)
+}
+
+func (m *ShipmentsServiceMock) AddEvent(ctx context.Context, trackingCode string, e domain.ShipmentEvent) (domain.ShipmentEvent, error) {
+	args := m.Called(ctx, trackingCode, e)
+	return args.Get(0).(domain.ShipmentEvent), args.Error(1)
+}
+
+func createShipmentsServer(s *Shipments) *gin.Engine {
+	r := gin.Default()
+	r.POST("/api/v1/shipments", s.Create())
+	r.GET("/api/v1/shipments/:tracking_code", s.Get())
+	r.POST("/api/v1/shipments/:tracking_code/events", s.CreateEvent())
+	return r
+}
+
+func TestCreateShipmentForBatches(t *testing.T) {
+	type response struct {
+		Data domain.Shipment
+	}
+	expected := domain.Shipment{ID: 1, TrackingCode: "TRABCDEFGHJK", CarryID: 2, ProductBatchIDs: []int{4, 5}, Status: shipments.StatusAssigned, Events: []domain.ShipmentEvent{}}
+	service := new(ShipmentsServiceMock)
+	service.On("Save", mock.Anything, domain.Shipment{CarryID: 2, ProductBatchIDs: []int{4, 5}}).Return(expected, nil)
+	r := createShipmentsServer(NewShipments(service))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/shipments", `{"carry_id": 2, "product_batch_ids": [4, 5]}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var objRes response
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &objRes))
+	assert.Equal(t, "TRABCDEFGHJK", objRes.Data.TrackingCode)
+}
+
+func TestCreateShipmentErrors(t *testing.T) {
+	cases := map[error]int{
+		shipments.ErrInvalidContent:                                            http.StatusUnprocessableEntity,
+		shipments.ErrCarryNotFound:                                             http.StatusConflict,
+		fmt.Errorf("%w: %d", shipments.ErrProductBatchNotFound, 5):             http.StatusConflict,
+		shipments.ErrPurchaseOrderNotReady:                                     http.StatusConflict,
+		fmt.Errorf("%w: connection reset", shipments.ErrPurchaseOrderNotFound): http.StatusConflict,
+	}
+	for err, status := range cases {
+		service := new(ShipmentsServiceMock)
+		service.On("Save", mock.Anything, mock.Anything).Return(domain.Shipment{}, err)
+		r := createShipmentsServer(NewShipments(service))
+		req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/shipments", `{"carry_id": 2, "purchase_order_id": 7}`)
+		r.ServeHTTP(rr, req)
+		assert.Equal(t, status, rr.Code, err.Error())
+	}
+}
+
+func TestCreateShipmentWithoutCarry(t *testing.T) {
+	service := new(ShipmentsServiceMock)
+	r := createShipmentsServer(NewShipments(service))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/shipments", `{"purchase_order_id": 7}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestGetShipmentByTrackingCode(t *testing.T) {
+	service := new(ShipmentsServiceMock)
+	service.On("Get", mock.Anything, "TRABCDEFGHJK").Return(domain.Shipment{ID: 1, TrackingCode: "TRABCDEFGHJK"}, nil)
+	service.On("Get", mock.Anything, "TRUNKNOWN").Return(domain.Shipment{}, shipments.ErrNotFound)
+	r := createShipmentsServer(NewShipments(service))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/shipments/TRABCDEFGHJK", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	req, rr = createRequestInboudOrdersTest(http.MethodGet, "/api/v1/shipments/TRUNKNOWN", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestCreateShipmentEventInvalidTransition(t *testing.T) {
+	service := new(ShipmentsServiceMock)
+	service.On("AddEvent", mock.Anything, "TRABCDEFGHJK", domain.ShipmentEvent{Type: shipments.StatusDelivered}).
+		Return(domain.ShipmentEvent{}, fmt.Errorf("%w: assigned -> delivered", shipments.ErrInvalidTransition))
+	r := createShipmentsServer(NewShipments(service))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/shipments/TRABCDEFGHJK/events", `{"type": "delivered"}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/stockMovements"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type StockMovements struct {
+	stockMovementsService stockMovements.Service
+}
+
+func NewStockMovements(s stockMovements.Service) *StockMovements {
+	return &StockMovements{
+		stockMovementsService: s,
+	}
+}
+
+// ListStockMovements godoc
+// @Summary List stock movements
+// @Tag StockMovements
+// @Description get a page of the stock movements ledger in chronological order, optionally filtered
+// @Produce json
+// @Param type             query string false "inbound, pick, adjustment, transfer or write-off"
+// @Param product_batch_id query int    false "Product batch ID"
+// @Param section_id       query int    false "Section ID"
+// @Param employee_id      query int    false "Employee ID"
+// @Param from             query string false "Start date (YYYY-MM-DD or RFC3339), inclusive"
+// @Param to               query string false "End date, exclusive (RFC3339) or inclusive (YYYY-MM-DD)"
+// @Param page             query int    false "Page, starting at 1"
+// @Param limit            query int    false "Page size (default 20, max 100)"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Router /api/v1/stockMovements [get]
+func (s *StockMovements) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := domain.StockMovementFilter{Type: c.Query("type")}
+		ints := map[string]*int{
+			"product_batch_id": &filter.ProductBatchID,
+			"section_id":       &filter.SectionID,
+			"employee_id":      &filter.EmployeeID,
+			"page":             &filter.Page,
+			"limit":            &filter.Limit,
+		}
+		for param, target := range ints {
+			if c.Query(param) == "" {
+				continue
+			}
+			value, err := strconv.Atoi(c.Query(param))
+			if err != nil {
+				web.Error(c, http.StatusBadRequest, "Error: invalid %s", param)
+				return
+			}
+			*target = value
+		}
+		var err error
+		if c.Query("from") != "" {
+			if filter.From, err = parseQueryTime(c.Query("from")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro from debe tener el formato YYYY-MM-DD o RFC3339")
+				return
+			}
+		}
+		if c.Query("to") != "" {
+			if filter.To, err = parseQueryEndTime(c.Query("to")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro to debe tener el formato YYYY-MM-DD o RFC3339")
+				return
+			}
+		}
+		ctx := context.Background()
+		page, err := s.stockMovementsService.GetAll(ctx, filter)
+		if err != nil {
+			if errors.Is(err, stockMovements.ErrInvalidType) || errors.Is(err, stockMovements.ErrInvalidRange) || errors.Is(err, stockMovements.ErrInvalidFilter) {
+				web.Error(c, http.StatusBadRequest, "%s", err.Error())
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+			return
+		}
+		web.Success(c, http.StatusOK, page)
+	}
+}
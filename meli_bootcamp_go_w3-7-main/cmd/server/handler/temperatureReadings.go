@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/alerts"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/temperatureReadings"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/web"
+	"github.com/gin-gonic/gin"
+)
+
+type TemperatureReadings struct {
+	temperatureReadingsService temperatureReadings.Service
+	alertsService              alerts.Service
+}
+
+func NewTemperatureReadings(t temperatureReadings.Service, a alerts.Service) *TemperatureReadings {
+	return &TemperatureReadings{
+		temperatureReadingsService: t,
+		alertsService:              a,
+	}
+}
+
+const (
+	maxTemperatureReadings     = 1000
+	maxTemperatureReadingsSize = 1 << 20
+)
+
+type temperatureReadingRequest struct {
+	Temperature *float32  `json:"temperature"`
+	ReadAt      time.Time `json:"read_at"`
+}
+
+// CreateTemperatureReadings godoc
+// @Summary Ingest section temperature readings
+// @Tag TemperatureReadings
+// @Description store a single reading or a list of readings for a section and update its current temperature with the latest one
+// @Accept json
+// @Produce json
+// @Param        id   path      int  true  "Section ID"
+// @Success 201 {object} web.response
+// @Failure 404 {object} web.errorResponse
+// @Failure 413 {object} web.errorResponse
+// @Failure 422 {object} web.errorResponse
+// @Router /api/v1/sections/{id}/temperatureReadings [post]
+func (t *TemperatureReadings) Create() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTemperatureReadingsSize)
+		body, err := c.GetRawData()
+		if err != nil {
+			web.Error(c, http.StatusRequestEntityTooLarge, "El body no puede superar los %d bytes", maxTemperatureReadingsSize)
+			return
+		}
+		var reqs []temperatureReadingRequest
+		if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(trimmed, &reqs)
+		} else {
+			var req temperatureReadingRequest
+			err = json.Unmarshal(trimmed, &req)
+			reqs = append(reqs, req)
+		}
+		if err != nil {
+			web.Error(c, http.StatusUnprocessableEntity, "%s", "El body debe ser una lectura o una lista de lecturas con temperature y read_at (RFC3339)")
+			return
+		}
+		if len(reqs) > maxTemperatureReadings {
+			web.Error(c, http.StatusRequestEntityTooLarge, "No se pueden cargar mas de %d lecturas por request", maxTemperatureReadings)
+			return
+		}
+		readings := make([]domain.TemperatureReading, 0, len(reqs))
+		for i, req := range reqs {
+			if req.Temperature == nil || req.ReadAt.IsZero() {
+				web.Error(c, http.StatusUnprocessableEntity, "La lectura %d requiere los campos temperature y read_at", i)
+				return
+			}
+			readings = append(readings, domain.TemperatureReading{Temperature: *req.Temperature, ReadAt: req.ReadAt})
+		}
+		ctx := context.Background()
+		saved, err := t.temperatureReadingsService.Save(ctx, int(id), readings)
+		if err != nil {
+			switch {
+			case errors.Is(err, temperatureReadings.ErrSectionNotFound):
+				web.Error(c, http.StatusNotFound, "No existe la seccion con el id %d", id)
+				return
+			case errors.Is(err, temperatureReadings.ErrNoReadings):
+				web.Error(c, http.StatusUnprocessableEntity, "%s", err.Error())
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		if _, err := t.alertsService.EvaluateSection(ctx, int(id)); err != nil {
+			log.Printf("evaluating alerts of section %d: %v", id, err)
+		}
+		web.Success(c, http.StatusCreated, saved)
+	}
+}
+
+// GetTemperatureReadings godoc
+// @Summary Section temperature history
+// @Tag TemperatureReadings
+// @Description get min, max and avg temperature of a section for every interval between from and to
+// @Produce json
+// @Param        id       path   int     true   "Section ID"
+// @Param        from     query  string  false  "Start date (YYYY-MM-DD or RFC3339), defaults to 24 hours before to"
+// @Param        to       query  string  false  "End date, exclusive (RFC3339) or inclusive (YYYY-MM-DD), defaults to now"
+// @Param        interval query  string  false  "Aggregation interval, e.g. 15m or 1h (default 1h)"
+// @Success 200 {object} web.response
+// @Failure 400 {object} web.errorResponse
+// @Failure 404 {object} web.errorResponse
+// @Router /api/v1/sections/{id}/temperatureReadings [get]
+func (t *TemperatureReadings) Get() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "Error: %s", "invalid ID")
+			return
+		}
+		to := time.Now().UTC()
+		if c.Query("to") != "" {
+			if to, err = parseQueryEndTime(c.Query("to")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro to debe tener el formato YYYY-MM-DD o RFC3339")
+				return
+			}
+		}
+		from := to.Add(-24 * time.Hour)
+		if c.Query("from") != "" {
+			if from, err = parseQueryTime(c.Query("from")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro from debe tener el formato YYYY-MM-DD o RFC3339")
+				return
+			}
+		}
+		interval := time.Hour
+		if c.Query("interval") != "" {
+			if interval, err = time.ParseDuration(c.Query("interval")); err != nil {
+				web.Error(c, http.StatusBadRequest, "%s", "El parametro interval debe ser una duracion como 15m o 1h")
+				return
+			}
+		}
+		ctx := context.Background()
+		aggregates, err := t.temperatureReadingsService.GetAggregates(ctx, int(id), from, to, interval)
+		if err != nil {
+			switch {
+			case errors.Is(err, temperatureReadings.ErrSectionNotFound):
+				web.Error(c, http.StatusNotFound, "No existe la seccion con el id %d", id)
+				return
+			case errors.Is(err, temperatureReadings.ErrInvalidRange):
+				web.Error(c, http.StatusBadRequest, "%s", err.Error())
+				return
+			default:
+				web.Error(c, http.StatusInternalServerError, "Error: %s", err.Error())
+				return
+			}
+		}
+		web.Success(c, http.StatusOK, aggregates)
+	}
+}
+
+func parseQueryTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// parseQueryEndTime parses the exclusive upper bound of a range. A date
+// without time includes that whole day, so it is moved to the next midnight.
+func parseQueryEndTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return t, err
+	}
+	return t.AddDate(0, 0, 1), nil
+}
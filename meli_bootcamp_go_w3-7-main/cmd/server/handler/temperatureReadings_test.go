@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/temperatureReadings"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type dbTRMock struct {
+	mock.Mock
+}
+
+func (m *dbTRMock) ExistsSection(ctx context.Context, sectionId int) bool {
+	args := m.Called(ctx, sectionId)
+	return args.Bool(0)
+}
+
+func (m *dbTRMock) Save(ctx context.Context, sectionId int, readings []domain.TemperatureReading) ([]int, error) {
+	args := m.Called(ctx, sectionId, readings)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *dbTRMock) GetBySection(ctx context.Context, sectionId int, from, to time.Time) ([]domain.TemperatureReading, error) {
+	args := m.Called(ctx, sectionId, from, to)
+	return args.Get(0).([]domain.TemperatureReading), args.Error(1)
+}
+
+func newAlertsServiceMockTR() *AlertsServiceMock {
+	alertsService := new(AlertsServiceMock)
+	alertsService.On("EvaluateSection", mock.Anything, mock.Anything).Return([]domain.Alert{}, nil)
+	return alertsService
+}
+
+func createServiceTR(t *TemperatureReadings) *gin.Engine {
+	r := gin.Default()
+	r.POST("/api/v1/sections/:id/temperatureReadings", t.Create())
+	r.GET("/api/v1/sections/:id/temperatureReadings", t.Get())
+	return r
+}
+
+func TestCreateSingleTemperatureReadings(t *testing.T) {
+	repo := new(dbTRMock)
+	repo.On("ExistsSection", mock.Anything, 1).Return(true)
+	repo.On("Save", mock.Anything, 1, mock.MatchedBy(func(r []domain.TemperatureReading) bool { return len(r) == 1 })).Return([]int{1}, nil)
+	r := createServiceTR(NewTemperatureReadings(temperatureReadings.NewService(repo), newAlertsServiceMockTR()))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/sections/1/temperatureReadings", `{"temperature": 0, "read_at": "2022-01-06T10:00:00Z"}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestCreateBulkTemperatureReadings(t *testing.T) {
+	repo := new(dbTRMock)
+	repo.On("ExistsSection", mock.Anything, 1).Return(true)
+	repo.On("Save", mock.Anything, 1, mock.MatchedBy(func(r []domain.TemperatureReading) bool { return len(r) == 2 })).Return([]int{1, 2}, nil)
+	r := createServiceTR(NewTemperatureReadings(temperatureReadings.NewService(repo), newAlertsServiceMockTR()))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/sections/1/temperatureReadings", `[
+		{"temperature": -18, "read_at": "2022-01-06T10:00:00Z"},
+		{"temperature": -17.5, "read_at": "2022-01-06T10:05:00Z"}
+	]`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestCreateMissingFieldsTemperatureReadings(t *testing.T) {
+	repo := new(dbTRMock)
+	r := createServiceTR(NewTemperatureReadings(temperatureReadings.NewService(repo), newAlertsServiceMockTR()))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/sections/1/temperatureReadings", `[{"temperature": -18}]`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestCreateSectionNotFoundTemperatureReadings(t *testing.T) {
+	repo := new(dbTRMock)
+	repo.On("ExistsSection", mock.Anything, 9).Return(false)
+	r := createServiceTR(NewTemperatureReadings(temperatureReadings.NewService(repo), newAlertsServiceMockTR()))
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/sections/9/temperatureReadings", `{"temperature": -18, "read_at": "2022-01-06T10:00:00Z"}`)
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetBadIntervalTemperatureReadings(t *testing.T) {
+	repo := new(dbTRMock)
+	r := createServiceTR(NewTemperatureReadings(temperatureReadings.NewService(repo), newAlertsServiceMockTR()))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/sections/1/temperatureReadings?interval=hourly", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreateTooManyTemperatureReadings(t *testing.T) {
+	repo := new(dbTRMock)
+	r := createServiceTR(NewTemperatureReadings(temperatureReadings.NewService(repo), newAlertsServiceMockTR()))
+	readings := make([]string, maxTemperatureReadings+1)
+	for i := range readings {
+		readings[i] = `{"temperature": -18, "read_at": "2022-01-06T10:00:00Z"}`
+	}
+	req, rr := createRequestInboudOrdersTest(http.MethodPost, "/api/v1/sections/1/temperatureReadings", "["+strings.Join(readings, ",")+"]")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetDateOnlyToIncludesWholeDayTemperatureReadings(t *testing.T) {
+	repo := new(dbTRMock)
+	from := time.Date(2022, 1, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 7, 0, 0, 0, 0, time.UTC)
+	repo.On("ExistsSection", mock.Anything, 1).Return(true)
+	repo.On("GetBySection", mock.Anything, 1, from, to).Return([]domain.TemperatureReading{}, nil)
+	r := createServiceTR(NewTemperatureReadings(temperatureReadings.NewService(repo), newAlertsServiceMockTR()))
+	req, rr := createRequestInboudOrdersTest(http.MethodGet, "/api/v1/sections/1/temperatureReadings?from=2022-01-06&to=2022-01-06", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	repo.AssertExpectations(t)
+}
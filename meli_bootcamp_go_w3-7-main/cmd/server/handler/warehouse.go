@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"strconv"
 
 	"net/http"
@@ -153,9 +154,11 @@ func (w *Warehouse) Update() gin.HandlerFunc {
 // @Description delete a warehouse
 // @Accept json
 // @Produce json
-// @Param        id   path      int  true  "Warehouse ID"
+// @Param        id     path      int   true   "Warehouse ID"
+// @Param        force  query     bool  false  "Also delete its sections, employees, inbound orders and the batches, temperature readings and alerts of its sections. Refused while its sections hold stock or have stock movements or shipped batches"
 // @Success      204  {object}  web.response
 // @Failure      404  {object}  web.errorResponse
+// @Failure      409  {object}  web.errorResponse
 // @Failure      500  {object}  web.errorResponse
 // @Router       /api/v1/warehouses/{id} [delete]
 func (w *Warehouse) Delete() gin.HandlerFunc {
@@ -166,13 +169,25 @@ func (w *Warehouse) Delete() gin.HandlerFunc {
 			return
 		}
 		ctx := context.Background()
-		warehouse, err := w.warehouseService.Get(ctx, int(id))
-		if warehouse.ID == 0 {
+		warehouseById, err := w.warehouseService.Get(ctx, int(id))
+		if warehouseById.ID == 0 {
 			web.Error(c, http.StatusNotFound, "No se encuentra el warehouse con id: %d", id)
 			return
 		}
-		err = w.warehouseService.Delete(ctx, int(id))
+		if c.Query("force") == "true" {
+			err = w.warehouseService.DeleteCascade(ctx, int(id))
+		} else {
+			err = w.warehouseService.Delete(ctx, int(id))
+		}
 		if err != nil {
+			if errors.Is(err, warehouse.ErrHasDependents) {
+				web.Error(c, http.StatusConflict, "No se puede eliminar el warehouse, tiene registros asociados (%s). Use force=true para eliminarlos", err)
+				return
+			}
+			if errors.Is(err, warehouse.ErrHasStock) {
+				web.Error(c, http.StatusConflict, "No se puede eliminar el warehouse ni con force=true, su stock y su historial deben conservarse (%s)", err)
+				return
+			}
 			web.Error(c, http.StatusInternalServerError, "Ocurrió un error al intentar eliminar el warehouse: %s", err)
 			return
 		}
@@ -180,6 +195,37 @@ func (w *Warehouse) Delete() gin.HandlerFunc {
 	}
 }
 
+// GetWarehouseOccupancy godoc
+// @Summary      Get Warehouse occupancy
+// @Description  get the capacity and utilisation of every section of a warehouse, flagging the ones below minimum or nearly full
+// @Tags         warehouses
+// @Produce      json
+// @Param        id   path      int  true  "Warehouse ID"
+// @Success      200  {object}  web.response
+// @Failure      404  {object}  web.errorResponse
+// @Failure      500  {object}  web.errorResponse
+// @Router       /api/v1/warehouses/{id}/occupancy [get]
+func (w *Warehouse) GetOccupancy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			web.Error(c, http.StatusBadRequest, "%s", "El id ingresado no es válido")
+			return
+		}
+		ctx := context.Background()
+		occupancy, err := w.warehouseService.GetOccupancy(ctx, int(id))
+		if err != nil {
+			if errors.Is(err, warehouse.ErrNotFound) {
+				web.Error(c, http.StatusNotFound, "No se encuentra el warehouse con id: %d", id)
+				return
+			}
+			web.Error(c, http.StatusInternalServerError, "Se presentó un problema en la consulta: %s", err)
+			return
+		}
+		web.Success(c, http.StatusOK, occupancy)
+	}
+}
+
 func updateFields(lastW domain.Warehouse, newW domain.Warehouse, id int) domain.Warehouse {
 	if newW.Address != lastW.Address && newW.Address != "" {
 		lastW.Address = newW.Address
@@ -3,7 +3,9 @@ package handler
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -45,6 +47,21 @@ func (m *ServiceM) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *ServiceM) GetDependents(ctx context.Context, id int) (domain.WarehouseDependents, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.WarehouseDependents), args.Error(1)
+}
+
+func (m *ServiceM) DeleteCascade(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ServiceM) GetSectionsOccupancy(ctx context.Context, warehouseId int) ([]domain.SectionOccupancy, error) {
+	args := m.Called(ctx, warehouseId)
+	return args.Get(0).([]domain.SectionOccupancy), args.Error(1)
+}
+
 func (m *ServiceM) Exists(ctx context.Context, warehouseCode string) bool {
 	args := m.Called(ctx, warehouseCode)
 	return args.Bool(0)
@@ -56,6 +73,7 @@ func createServer(w *Warehouse) *gin.Engine {
 	{
 		wr.GET("/", w.GetAll())
 		wr.GET("/:id", w.Get())
+		wr.GET("/:id/occupancy", w.GetOccupancy())
 		wr.POST("/", w.Create())
 		wr.PATCH("/:id", w.Update())
 		wr.DELETE("/:id", w.Delete())
@@ -277,6 +295,7 @@ func TestDeleteWarehouseOK(t *testing.T) {
 	}
 	s := new(ServiceM)
 	s.On("Get", mock.Anything, mock.Anything).Return(mockResponse, nil)
+	s.On("GetDependents", mock.Anything, 1).Return(domain.WarehouseDependents{}, nil)
 	s.On("Delete", mock.Anything, mock.Anything).Return(nil)
 	service := warehouse.NewService(s)
 	w := NewWarehouse(service)
@@ -311,3 +330,60 @@ func TestUpdateWarehouseFail(t *testing.T) {
 	r.ServeHTTP(res, req)
 	assert.Equal(t, http.StatusBadRequest, res.Code)
 }
+
+func TestGetWarehouseOccupancy(t *testing.T) {
+	expectedResponse := `{"data":{"warehouse_id":1,"warehouse_code":"HCD","current_capacity":95,"minimum_capacity":30,"maximum_capacity":100,"utilization":95,"below_minimum":false,"sections":[{"section_id":4,"section_number":1,"current_capacity":95,"minimum_capacity":10,"maximum_capacity":100,"utilization":95,"below_minimum":false,"nearly_full":true}],"sections_below_minimum":[],"sections_nearly_full":[4]}}`
+	s := new(ServiceM)
+	s.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1, WarehouseCode: "HCD", MinimumCapacity: 30}, nil)
+	s.On("GetSectionsOccupancy", mock.Anything, 1).Return([]domain.SectionOccupancy{
+		{SectionID: 4, SectionNumber: 1, CurrentCapacity: 95, MinimumCapacity: 10, MaximumCapacity: 100},
+	}, nil)
+	r := createServer(NewWarehouse(warehouse.NewService(s)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/warehouses/1/occupancy", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, expectedResponse, rr.Body.String())
+}
+
+func TestGetOccupancyNonExistWarehouse(t *testing.T) {
+	s := new(ServiceM)
+	s.On("Get", mock.Anything, 9).Return(domain.Warehouse{}, sql.ErrNoRows)
+	r := createServer(NewWarehouse(warehouse.NewService(s)))
+	req, rr := createRequestTest(http.MethodGet, "/api/v1/warehouses/9/occupancy", "")
+	r.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDeleteWarehouseWithDependents(t *testing.T) {
+	s := new(ServiceM)
+	s.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+	s.On("GetDependents", mock.Anything, 1).Return(domain.WarehouseDependents{SectionIDs: []int{2}, EmployeeIDs: []int{5, 6}}, nil)
+	r := createServer(NewWarehouse(warehouse.NewService(s)))
+	req, res := createRequestTest(http.MethodDelete, "/api/v1/warehouses/1", "")
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Contains(t, res.Body.String(), "sections [2], employees [5 6]")
+	s.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteWarehouseForce(t *testing.T) {
+	s := new(ServiceM)
+	s.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+	s.On("DeleteCascade", mock.Anything, 1).Return(nil)
+	r := createServer(NewWarehouse(warehouse.NewService(s)))
+	req, res := createRequestTest(http.MethodDelete, "/api/v1/warehouses/1?force=true", "")
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	s.AssertNotCalled(t, "GetDependents", mock.Anything, mock.Anything)
+}
+
+func TestForceDeleteWarehouseWithStock(t *testing.T) {
+	s := new(ServiceM)
+	s.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+	s.On("DeleteCascade", mock.Anything, 1).Return(fmt.Errorf("%w: stock 10, stock movements 3, shipment batches 0", warehouse.ErrHasStock))
+	r := createServer(NewWarehouse(warehouse.NewService(s)))
+	req, res := createRequestTest(http.MethodDelete, "/api/v1/warehouses/1?force=true", "")
+	r.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Contains(t, res.Body.String(), "stock movements 3")
+}
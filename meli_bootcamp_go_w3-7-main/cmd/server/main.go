@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"os"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/cmd/server/routes"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/docs"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/productBatches"
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
@@ -30,6 +33,8 @@ func main() {
 	router := routes.NewRouter(r, db)
 	router.MapRoutes()
 
+	go productBatches.RunExpiryJob(context.Background(), productBatches.NewService(productBatches.NewRepository(db)), time.Hour)
+
 	if err := r.Run(); err != nil {
 		panic(err)
 	}
@@ -2,17 +2,29 @@ package routes
 
 import (
 	"database/sql"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/product_record"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/cmd/server/handler"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/alerts"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/buyer"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/carry"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/country"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/employee"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/inboudOrders"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/locality"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/product"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/productType"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/province"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/purchaseOrders"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/section"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/seller"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/shipments"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/stockMovements"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/temperatureReadings"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/warehouse"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/productBatches"
@@ -30,6 +42,9 @@ type router struct {
 	r  *gin.Engine
 	rg *gin.RouterGroup
 	db *sql.DB
+	// alerts is shared by every handler that evaluates alerts, so they all use the same
+	// webhook notifier.
+	alerts alerts.Service
 }
 
 func NewRouter(r *gin.Engine, db *sql.DB) Router {
@@ -39,17 +54,26 @@ func NewRouter(r *gin.Engine, db *sql.DB) Router {
 func (r *router) MapRoutes() {
 	r.buildSwaggerRoutes()
 	r.setGroup()
+	r.alerts = r.newAlertsService()
 	r.buildSellerRoutes()
 	r.buildProductRoutes()
+	r.buildProductTypeRoutes()
 	r.buildSectionRoutes()
+	r.buildTemperatureReadingsRoutes()
+	r.buildAlertsRoutes()
 	r.buildWarehouseRoutes()
 	r.buildEmployeeRoutes()
 	r.buildInboudOrdersRoutes()
 	r.buildBuyerRoutes()
+	r.buildPurchaseOrdersRoutes()
 	r.buildProductRecordRoutes()
 	r.buildCarryRoutes()
+	r.buildCountryRoutes()
+	r.buildProvinceRoutes()
 	r.buildLocalityRoutes()
+	r.buildShipmentsRoutes()
 	r.buildProductBatchesRoutes()
+	r.buildStockMovementsRoutes()
 }
 
 func (r *router) buildSwaggerRoutes() {
@@ -91,10 +115,24 @@ func (r *router) buildProductRoutes() {
 	}
 }
 
+func (r *router) buildProductTypeRoutes() {
+	repo := productType.NewRepository(r.db)
+	service := productType.NewService(repo)
+	handler := handler.NewProductType(service)
+	productTypesRouter := r.rg.Group("/productTypes")
+	{
+		productTypesRouter.GET("/", handler.GetAll())
+		productTypesRouter.GET("/:id", handler.Get())
+		productTypesRouter.POST("/", handler.Create())
+		productTypesRouter.PATCH("/:id", handler.Update())
+		productTypesRouter.DELETE("/:id", handler.Delete())
+	}
+}
+
 func (r *router) buildSectionRoutes() {
 	repository := section.NewRepository(r.db)
 	service := section.NewService(repository)
-	handler := handler.NewSection(service)
+	handler := handler.NewSection(service, r.alerts)
 	r.rg.GET("/sections", handler.GetAll())
 	r.rg.GET("/sections/:id", handler.Get())
 	r.rg.POST("/sections", handler.Create())
@@ -102,12 +140,39 @@ func (r *router) buildSectionRoutes() {
 	r.rg.DELETE("/sections/:id", handler.Delete())
 }
 
+func (r *router) buildTemperatureReadingsRoutes() {
+	repository := temperatureReadings.NewRepository(r.db)
+	service := temperatureReadings.NewService(repository)
+	handler := handler.NewTemperatureReadings(service, r.alerts)
+	r.rg.POST("/sections/:id/temperatureReadings", handler.Create())
+	r.rg.GET("/sections/:id/temperatureReadings", handler.Get())
+}
+
+// newAlertsService delivers alert webhooks to the comma separated ALERT_WEBHOOK_URLS,
+// signed with ALERT_WEBHOOK_SECRET.
+func (r *router) newAlertsService() alerts.Service {
+	var endpoints []string
+	for _, endpoint := range strings.Split(os.Getenv("ALERT_WEBHOOK_URLS"), ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	notifier := alerts.NewWebhookNotifier(endpoints, os.Getenv("ALERT_WEBHOOK_SECRET"), 5, time.Second)
+	return alerts.NewService(alerts.NewRepository(r.db), notifier)
+}
+
+func (r *router) buildAlertsRoutes() {
+	handler := handler.NewAlerts(r.alerts)
+	r.rg.GET("/alerts", handler.GetAll())
+}
+
 func (r *router) buildWarehouseRoutes() {
 	warehouseRepo := warehouse.NewRepository(r.db)
 	warehouseService := warehouse.NewService(warehouseRepo)
 	warehouseHandler := handler.NewWarehouse(warehouseService)
 	r.rg.GET("/warehouses", warehouseHandler.GetAll())
 	r.rg.GET("/warehouses/:id", warehouseHandler.Get())
+	r.rg.GET("/warehouses/:id/occupancy", warehouseHandler.GetOccupancy())
 	r.rg.POST("/warehouses", warehouseHandler.Create())
 	r.rg.PATCH("/warehouses/:id", warehouseHandler.Update())
 	r.rg.DELETE("/warehouses/:id", warehouseHandler.Delete())
@@ -121,17 +186,21 @@ func (r *router) buildEmployeeRoutes() {
 	{
 		employeesRouter.GET("/", handler.GetAll())
 		employeesRouter.GET("/:id", handler.Get())
+		employeesRouter.GET("/:id/assignments", handler.GetAssignments())
 		employeesRouter.GET("/reportinboundorders", handler.GetReportIO())
 		employeesRouter.POST("/", handler.Create())
 		employeesRouter.PATCH("/:id", handler.Update())
 		employeesRouter.DELETE("/:id", handler.Delete())
 	}
+	r.rg.GET("/warehouses/:id/employees", handler.GetByWarehouse())
 }
 
 func (r *router) buildInboudOrdersRoutes() {
 	repo := inboudOrders.NewRepository(r.db)
 	service := inboudOrders.NewService(repo)
 	handler := handler.NewInboudOrders(service)
+	r.rg.GET("/inboundOrders", handler.GetAll())
+	r.rg.GET("/inboundOrders/:id", handler.Get())
 	r.rg.POST("/inboundOrders", handler.Create())
 }
 
@@ -156,6 +225,7 @@ func (r *router) buildCarryRoutes() {
 	carryService := carry.NewService(carryRepo)
 	carryHandler := handler.NewCarry(carryService)
 	r.rg.GET("/carries", carryHandler.GetAll())
+	r.rg.GET("/carries/eligible", carryHandler.GetEligible())
 	r.rg.GET("/carries/:id", carryHandler.Get())
 	r.rg.POST("/carries", carryHandler.Create())
 	r.rg.PATCH("/carries/:id", carryHandler.Update())
@@ -164,20 +234,98 @@ func (r *router) buildCarryRoutes() {
 	r.rg.GET("/localities/reportCarries", carryHandler.GetCarriesReport())
 }
 
+func (r *router) buildCountryRoutes() {
+	repo := country.NewRepository(r.db)
+	service := country.NewService(repo)
+	handler := handler.NewCountry(service)
+	countriesRouter := r.rg.Group("/countries")
+	{
+		countriesRouter.GET("/", handler.GetAll())
+		countriesRouter.GET("/:id", handler.Get())
+		countriesRouter.POST("/", handler.Create())
+		countriesRouter.PATCH("/:id", handler.Update())
+		countriesRouter.DELETE("/:id", handler.Delete())
+	}
+}
+
+func (r *router) buildProvinceRoutes() {
+	repo := province.NewRepository(r.db)
+	service := province.NewService(repo)
+	handler := handler.NewProvince(service)
+	provincesRouter := r.rg.Group("/provinces")
+	{
+		provincesRouter.GET("/", handler.GetAll())
+		provincesRouter.GET("/:id", handler.Get())
+		provincesRouter.POST("/", handler.Create())
+		provincesRouter.PATCH("/:id", handler.Update())
+		provincesRouter.DELETE("/:id", handler.Delete())
+	}
+}
+
 func (r *router) buildLocalityRoutes() {
 	repo := locality.NewRepository(r.db)
-	service := locality.NewService(repo)
+	service := locality.NewService(repo, carry.NewService(carry.NewRepository(r.db)))
 	handler := handler.NewLocality(service)
+	r.rg.GET("/localities", handler.GetAll())
+	r.rg.GET("/localities/:id", handler.GetByID())
 	r.rg.POST("/localities", handler.Create())
+	r.rg.POST("/localities/import", handler.Import())
+	r.rg.PATCH("/localities/:id", handler.Update())
+	r.rg.DELETE("/localities/:id", handler.Delete())
+	r.rg.GET("/localities/:id/report", handler.GetReport())
 	r.rg.GET("/localities/reportSellers", handler.Get())
 }
 
+func (r *router) buildShipmentsRoutes() {
+	repo := shipments.NewRepository(r.db)
+	service := shipments.NewService(repo)
+	handler := handler.NewShipments(service)
+	r.rg.POST("/shipments", handler.Create())
+	r.rg.GET("/shipments/:tracking_code", handler.Get())
+	r.rg.POST("/shipments/:tracking_code/events", handler.CreateEvent())
+}
+
 func (r *router) buildProductBatchesRoutes() {
 	repo := productBatches.NewRepository(r.db)
 	service := productBatches.NewService(repo)
-	handler := handler.NewProductBatches(service)
+	handler := handler.NewProductBatches(service, r.alerts)
 	r.rg.POST("/productBatches", handler.CreateProductBatches())
-	//r.rg.GET("/sections/reportProducts", handler.GetProductBatches())
+	r.rg.POST("/productBatches/pick", handler.Pick())
+	r.rg.GET("/productBatches/expiring", handler.GetExpiring())
+	r.rg.GET("/sections/reportProducts", handler.GetProductBatches())
+}
+
+func (r *router) buildStockMovementsRoutes() {
+	repo := stockMovements.NewRepository(r.db)
+	service := stockMovements.NewService(repo)
+	handler := handler.NewStockMovements(service)
+	r.rg.GET("/stockMovements", handler.GetAll())
 }
 
-func (r *router) buildBuyerRoutes() {}
+func (r *router) buildBuyerRoutes() {
+	repo := buyer.NewRepository(r.db)
+	service := buyer.NewService(repo)
+	handler := handler.NewBuyer(service)
+	buyersRouter := r.rg.Group("/buyers")
+	{
+		buyersRouter.GET("/", handler.GetAll())
+		buyersRouter.GET("/:id", handler.Get())
+		buyersRouter.GET("/reportPurchaseOrders", handler.GetReportPO())
+		buyersRouter.POST("/", handler.Create())
+		buyersRouter.PATCH("/:id", handler.Update())
+		buyersRouter.DELETE("/:id", handler.Delete())
+	}
+}
+
+func (r *router) buildPurchaseOrdersRoutes() {
+	repo := purchaseOrders.NewRepository(r.db)
+	service := purchaseOrders.NewService(repo)
+	handler := handler.NewPurchaseOrders(service)
+	purchaseOrdersRouter := r.rg.Group("/purchaseOrders")
+	{
+		purchaseOrdersRouter.GET("/", handler.GetAll())
+		purchaseOrdersRouter.GET("/:id", handler.Get())
+		purchaseOrdersRouter.POST("/", handler.Create())
+		purchaseOrdersRouter.PATCH("/:id/status", handler.UpdateStatus())
+	}
+}
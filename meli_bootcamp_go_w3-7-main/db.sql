@@ -16,6 +16,10 @@ create table products(
     id_product_type int not null,
     id_seller int not null
 );
+create table product_types(
+    `id` int not null primary key auto_increment,
+    `description` text not null
+);
 create table employees(
     `id` int not null primary key auto_increment,
     card_number_id text not null,
@@ -23,6 +27,17 @@ create table employees(
     last_name text not null,
     warehouse_id int not null
 );
+create table employee_assignments(
+    `id` int not null primary key auto_increment,
+    employee_id int not null,
+    warehouse_id int not null,
+    start_date datetime not null,
+    end_date datetime null
+);
+create index employee_assignments_employee_id_index
+    on employee_assignments (employee_id, start_date);
+create index employee_assignments_warehouse_id_index
+    on employee_assignments (warehouse_id, start_date);
 create table warehouses(
     `id` int not null primary key auto_increment,
     `address` text null,
@@ -59,14 +74,51 @@ create table buyers(
 create unique index warehouses_id_uindex
     on warehouses (id);
 
+create unique index sections_warehouse_section_number_uindex
+    on sections (warehouse_id, section_number);
+
+alter table sections
+    add constraint sections_warehouses_id_fk
+        foreign key (warehouse_id) references warehouses (id);
+
+alter table employees
+    add constraint employees_warehouses_id_fk
+        foreign key (warehouse_id) references warehouses (id);
+
+CREATE TABLE countries
+(
+    `id` int not null primary key auto_increment,
+    country_name varchar(100) not null
+);
+
+create unique index countries_country_name_uindex
+    on countries (country_name);
+
+CREATE TABLE provinces
+(
+    `id` int not null primary key auto_increment,
+    province_name varchar(100) not null,
+    country_id    int          not null,
+    constraint provinces_countries_id_fk
+        foreign key (country_id) references countries (id)
+);
+
+create unique index provinces_country_province_name_uindex
+    on provinces (country_id, province_name);
+
 CREATE TABLE localities
 (
     `id` int not null primary key auto_increment,
+    zip_code      varchar(10) not null,
     locality_name TEXT,
-    province_name TEXT,
-    country_name  TEXT
+    province_id   int         not null,
+    constraint localities_provinces_id_fk
+        foreign key (province_id) references provinces (id)
 );
 
+create unique index localities_zip_code_uindex
+    on localities (zip_code);
+
 CREATE TABLE carries
 (
     cid          TEXT,
@@ -87,9 +139,11 @@ CREATE TABLE product_batches
     due_date            datetime,
     initial_quantity    int,
     manufacturing_date  datetime,
+    manufacturing_hour  int,
     minimum_temperature float,
     product_id          int,
-    section_id          int
+    section_id          int,
+    blocked             boolean not null default false
 );
 
 CREATE TABLE product_records
@@ -112,12 +166,113 @@ CREATE TABLE purchase_orders
     order_status_id   int
 );
 
+CREATE TABLE order_statuses
+(
+    `id` int not null primary key auto_increment,
+    description TEXT not null
+);
+
+INSERT INTO order_statuses (id, description) VALUES
+    (1, 'pending'),
+    (2, 'picking'),
+    (3, 'shipped'),
+    (4, 'delivered'),
+    (5, 'cancelled');
+
 CREATE TABLE inbound_orders
 (
     `id` int not null primary key auto_increment,
     order_date       datetime,
-    order_number     TEXT,
+    order_number     varchar(50) not null,
     employee_id      int,
     product_batch_id int,
-    warehouse_id     int
+    warehouse_id     int,
+    quantity         int not null default 0
+);
+
+create unique index inbound_orders_order_number_uindex
+    on inbound_orders (order_number);
+
+create index inbound_orders_employee_order_date_index
+    on inbound_orders (employee_id, order_date);
+
+CREATE TABLE section_temperature_readings
+(
+    `id` int not null primary key auto_increment,
+    section_id  int      not null,
+    temperature float    not null,
+    read_at     datetime not null
+);
+
+create index section_temperature_readings_section_read_at_index
+    on section_temperature_readings (section_id, read_at);
+
+CREATE TABLE alerts
+(
+    `id` int not null primary key auto_increment,
+    alert_type       TEXT     not null,
+    section_id       int      not null,
+    product_batch_id int      null,
+    temperature      float    not null,
+    reason           TEXT     not null,
+    status           TEXT     not null,
+    opened_at        datetime not null,
+    closed_at        datetime null,
+    open_key         varchar(64) as (if(status = 'open', concat(alert_type, ':', section_id, ':', coalesce(product_batch_id, 0)), null)) stored
+);
+
+create unique index alerts_open_key_uindex
+    on alerts (open_key);
+
+CREATE TABLE stock_movements
+(
+    `id` int not null primary key auto_increment,
+    movement_type      TEXT     not null,
+    product_batch_id   int      null,
+    section_id         int      not null,
+    quantity           int      not null,
+    employee_id        int      null,
+    reference_document TEXT     null,
+    created_at         datetime not null
+);
+
+create index stock_movements_created_at_index
+    on stock_movements (created_at);
+
+CREATE TABLE shipments
+(
+    `id` int not null primary key auto_increment,
+    tracking_code     varchar(32) not null,
+    carry_id          int         not null,
+    purchase_order_id int         null,
+    status            TEXT        not null,
+    created_at        datetime    not null
+);
+
+create unique index shipments_tracking_code_uindex
+    on shipments (tracking_code);
+
+create unique index shipments_purchase_order_id_uindex
+    on shipments (purchase_order_id);
+
+create index shipments_carry_id_index
+    on shipments (carry_id);
+
+CREATE TABLE shipment_batches
+(
+    shipment_id      int not null,
+    product_batch_id int not null,
+    primary key (shipment_id, product_batch_id)
 );
+
+CREATE TABLE shipment_events
+(
+    `id` int not null primary key auto_increment,
+    shipment_id int      not null,
+    event_type  TEXT     not null,
+    location    TEXT     null,
+    occurred_at datetime not null
+);
+
+create index shipment_events_shipment_id_index
+    on shipment_events (shipment_id, occurred_at);
@@ -0,0 +1,117 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed with the webhook secret.
+	SignatureHeader = "X-Alert-Signature"
+	// EventHeader carries the event name so receivers can route without parsing the body.
+	EventHeader = "X-Alert-Event"
+)
+
+var ErrDeliveryFailed = errors.New("no se pudo entregar el webhook de la alerta")
+
+// Notifier delivers alert events to the outside world.
+type Notifier interface {
+	Notify(ctx context.Context, event domain.AlertEvent) error
+}
+
+type webhookNotifier struct {
+	endpoints   []string
+	secret      []byte
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewWebhookNotifier posts every event to each endpoint, retrying up to maxAttempts times
+// with an exponential backoff that starts at backoff. With no endpoints it does nothing.
+func NewWebhookNotifier(endpoints []string, secret string, maxAttempts int, backoff time.Duration) Notifier {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &webhookNotifier{
+		endpoints:   endpoints,
+		secret:      []byte(secret),
+		client:      &http.Client{Timeout: 5 * time.Second},
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+	}
+}
+
+// Sign returns the signature sent in SignatureHeader for the given body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, event domain.AlertEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	signature := Sign(string(n.secret), body)
+	var failed []string
+	for _, endpoint := range n.endpoints {
+		if err := n.deliver(ctx, endpoint, event.Event, signature, body); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, failed)
+	}
+	return nil
+}
+
+func (n *webhookNotifier) deliver(ctx context.Context, endpoint, event, signature string, body []byte) error {
+	for attempt := 1; ; attempt++ {
+		retry, err := n.post(ctx, endpoint, event, signature, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= n.maxAttempts {
+			return fmt.Errorf("%s tras %d intentos: %v", endpoint, attempt, err)
+		}
+		select {
+		case <-time.After(n.backoff << (attempt - 1)):
+		case <-ctx.Done():
+			return fmt.Errorf("%s: %v", endpoint, ctx.Err())
+		}
+	}
+}
+
+// post sends a single attempt and reports whether a failure is worth retrying:
+// network errors, 429 and 5xx are; any other non 2xx answer is final.
+func (n *webhookNotifier) post(ctx context.Context, endpoint, event, signature string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, signature)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}
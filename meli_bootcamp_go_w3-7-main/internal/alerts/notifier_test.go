@@ -0,0 +1,78 @@
+package alerts
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifySignedPayload(t *testing.T) {
+	var body []byte
+	var signature, event string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		event = r.Header.Get(EventHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	n := NewWebhookNotifier([]string{receiver.URL}, "s3cr3t", 3, time.Millisecond)
+	err := n.Notify(context.Background(), domain.AlertEvent{Event: EventOpened, Alert: domain.Alert{ID: 1, SectionID: 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, EventOpened, event)
+	assert.Equal(t, Sign("s3cr3t", body), signature)
+	assert.Contains(t, string(body), `"event":"alert.opened"`)
+}
+
+func TestNotifyRetriesWithBackoff(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	n := NewWebhookNotifier([]string{receiver.URL}, "s3cr3t", 5, time.Millisecond)
+	err := n.Notify(context.Background(), domain.AlertEvent{Event: EventOpened})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestNotifyGivesUp(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	n := NewWebhookNotifier([]string{receiver.URL}, "s3cr3t", 3, time.Millisecond)
+	err := n.Notify(context.Background(), domain.AlertEvent{Event: EventOpened})
+	assert.ErrorIs(t, err, ErrDeliveryFailed)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestNotifyDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+
+	n := NewWebhookNotifier([]string{receiver.URL}, "s3cr3t", 3, time.Millisecond)
+	err := n.Notify(context.Background(), domain.AlertEvent{Event: EventOpened})
+	assert.ErrorIs(t, err, ErrDeliveryFailed)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,177 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/store"
+)
+
+// Repository encapsulates the storage of temperature alerts.
+type Repository interface {
+	GetAll(ctx context.Context, status string) ([]domain.Alert, error)
+	GetOpen(ctx context.Context, alertType string, sectionId, productBatchId int) (domain.Alert, error)
+	Save(ctx context.Context, a domain.Alert) (int, error)
+	Close(ctx context.Context, id int, closedAt time.Time) error
+	GetSectionTemperature(ctx context.Context, sectionId int) (domain.SectionTemperature, error)
+	GetBatchTemperatures(ctx context.Context, sectionId int) ([]domain.BatchTemperature, error)
+}
+
+const (
+	selectAlertsQuery = "SELECT id, alert_type, section_id, product_batch_id, temperature, reason, status, opened_at, closed_at FROM alerts"
+	dateTimeLayout    = "2006-01-02 15:04:05"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll(ctx context.Context, status string) ([]domain.Alert, error) {
+	query := selectAlertsQuery
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE status=?"
+		args = append(args, status)
+	}
+	rows, err := r.db.Query(query+" ORDER BY opened_at DESC, id DESC;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []domain.Alert
+
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, nil
+}
+
+func (r *repository) GetOpen(ctx context.Context, alertType string, sectionId, productBatchId int) (domain.Alert, error) {
+	query := selectAlertsQuery + " WHERE status=? AND alert_type=? AND section_id=? AND COALESCE(product_batch_id, 0)=? LIMIT 1;"
+	row := r.db.QueryRow(query, StatusOpen, alertType, sectionId, productBatchId)
+	return scanAlert(row)
+}
+
+func (r *repository) Save(ctx context.Context, a domain.Alert) (int, error) {
+	query := "INSERT INTO alerts (alert_type, section_id, product_batch_id, temperature, reason, status, opened_at) VALUES (?, ?, ?, ?, ?, ?, ?);"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	productBatchId := sql.NullInt64{Int64: int64(a.ProductBatchID), Valid: a.ProductBatchID != 0}
+	res, err := stmt.Exec(a.Type, a.SectionID, productBatchId, a.Temperature, a.Reason, a.Status, a.OpenedAt.UTC().Format(dateTimeLayout))
+	if err != nil {
+		if store.IsDuplicateEntry(err) {
+			return 0, ErrAlreadyOpen
+		}
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) Close(ctx context.Context, id int, closedAt time.Time) error {
+	query := "UPDATE alerts SET status=?, closed_at=? WHERE id=? AND status=?;"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(StatusClosed, closedAt.UTC().Format(dateTimeLayout), id, StatusOpen)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotOpen
+	}
+	return nil
+}
+
+// GetSectionTemperature returns the section minimum and its latest reading as received,
+// falling back to sections.current_temperature, which only keeps the rounded value, when
+// the section has no readings yet.
+func (r *repository) GetSectionTemperature(ctx context.Context, sectionId int) (domain.SectionTemperature, error) {
+	query := "SELECT s.id, COALESCE((SELECT r.temperature FROM section_temperature_readings r WHERE r.section_id = s.id ORDER BY r.read_at DESC, r.id DESC LIMIT 1), s.current_temperature), s.minimum_temperature FROM sections s WHERE s.id=?;"
+	row := r.db.QueryRow(query, sectionId)
+	s := domain.SectionTemperature{}
+	err := row.Scan(&s.SectionID, &s.Temperature, &s.MinimumTemperature)
+	if err != nil {
+		return domain.SectionTemperature{}, err
+	}
+	return s, nil
+}
+
+func (r *repository) GetBatchTemperatures(ctx context.Context, sectionId int) ([]domain.BatchTemperature, error) {
+	query := "SELECT pb.id, pb.batch_number, pb.minimum_temperature, p.recommended_freezing_temperature FROM product_batches pb INNER JOIN products p ON p.id = pb.product_id WHERE pb.section_id=? AND pb.current_quantity > 0;"
+	rows, err := r.db.Query(query, sectionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []domain.BatchTemperature
+
+	for rows.Next() {
+		b := domain.BatchTemperature{}
+		if err := rows.Scan(&b.BatchID, &b.BatchNumber, &b.MinimumTemperature, &b.RecomFreezTemp); err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+
+	return batches, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAlert(row scanner) (domain.Alert, error) {
+	a := domain.Alert{}
+	var productBatchId sql.NullInt64
+	var openedAt string
+	var closedAt sql.NullString
+	err := row.Scan(&a.ID, &a.Type, &a.SectionID, &productBatchId, &a.Temperature, &a.Reason, &a.Status, &openedAt, &closedAt)
+	if err != nil {
+		return domain.Alert{}, err
+	}
+	a.ProductBatchID = int(productBatchId.Int64)
+	a.OpenedAt, err = time.Parse(dateTimeLayout, openedAt)
+	if err != nil {
+		return domain.Alert{}, err
+	}
+	if closedAt.Valid {
+		t, err := time.Parse(dateTimeLayout, closedAt.String)
+		if err != nil {
+			return domain.Alert{}, err
+		}
+		a.ClosedAt = &t
+	}
+	return a, nil
+}
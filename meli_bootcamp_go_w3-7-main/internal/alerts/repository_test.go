@@ -0,0 +1,24 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSectionTemperatureUsesLatestReading(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("COALESCE\\(\\(SELECT r.temperature FROM section_temperature_readings r WHERE r.section_id = s.id ORDER BY r.read_at DESC, r.id DESC LIMIT 1\\), s.current_temperature\\)").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "temperature", "minimum_temperature"}).AddRow(1, -20.4, -20))
+
+	sect, err := NewRepository(db).GetSectionTemperature(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(-20.4), sect.Temperature)
+	assert.Equal(t, -20, sect.MinimumTemperature)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
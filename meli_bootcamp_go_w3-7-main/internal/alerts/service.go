@@ -0,0 +1,159 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Errors
+var (
+	ErrSectionNotFound = errors.New("la seccion no fue encontrada")
+	ErrInvalidStatus   = errors.New("el status de la alerta debe ser open o closed")
+	ErrAlreadyOpen     = errors.New("ya existe una alerta abierta para el mismo sujeto")
+	ErrNotOpen         = errors.New("la alerta ya no esta abierta")
+)
+
+const (
+	AlertTypeSection = "section"
+	AlertTypeBatch   = "batch"
+
+	StatusOpen   = "open"
+	StatusClosed = "closed"
+
+	EventOpened = "alert.opened"
+	EventClosed = "alert.closed"
+)
+
+type Service interface {
+	GetAll(ctx context.Context, status string) ([]domain.Alert, error)
+	EvaluateSection(ctx context.Context, sectionId int) ([]domain.Alert, error)
+}
+
+type service struct {
+	repository Repository
+	notifier   Notifier
+}
+
+func NewService(repository Repository, notifier Notifier) Service {
+	return &service{
+		repository: repository,
+		notifier:   notifier,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context, status string) ([]domain.Alert, error) {
+	if status != "" && status != StatusOpen && status != StatusClosed {
+		return nil, ErrInvalidStatus
+	}
+	return s.repository.GetAll(ctx, status)
+}
+
+// EvaluateSection opens an alert for the section when its latest temperature reading is
+// below its minimum, and one per stored batch when that reading leaves the range between
+// the batch minimum_temperature and the product recommended_freezing_temperature. Open
+// alerts whose condition no longer holds are closed. It returns the alerts that were
+// opened or closed.
+func (s *service) EvaluateSection(ctx context.Context, sectionId int) ([]domain.Alert, error) {
+	sect, err := s.repository.GetSectionTemperature(ctx, sectionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSectionNotFound
+		}
+		return nil, err
+	}
+
+	temperature := sect.Temperature
+	changed := []domain.Alert{}
+	alert, ok, err := s.track(ctx, domain.Alert{
+		Type:        AlertTypeSection,
+		SectionID:   sectionId,
+		Temperature: temperature,
+		Reason:      fmt.Sprintf("la seccion %d esta a %.2f grados, por debajo de su minimo de %d grados", sectionId, temperature, sect.MinimumTemperature),
+	}, temperature < float32(sect.MinimumTemperature))
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		changed = append(changed, alert)
+	}
+
+	batches, err := s.repository.GetBatchTemperatures(ctx, sectionId)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range batches {
+		minTemperature, maxTemperature := b.MinimumTemperature, b.RecomFreezTemp
+		alert, ok, err := s.track(ctx, domain.Alert{
+			Type:           AlertTypeBatch,
+			SectionID:      sectionId,
+			ProductBatchID: b.BatchID,
+			Temperature:    temperature,
+			Reason:         fmt.Sprintf("el batch %d esta a %.2f grados en la seccion %d y su producto requiere entre %.2f y %.2f grados", b.BatchNumber, temperature, sectionId, minTemperature, maxTemperature),
+		}, temperature < minTemperature || temperature > maxTemperature)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			changed = append(changed, alert)
+		}
+	}
+	return changed, nil
+}
+
+// track opens candidate when there is an excursion and no open alert for the same
+// subject, or closes the open alert once the excursion is over. Concurrent evaluations
+// are settled by the alerts_open_key_uindex unique index and the conditional close: the
+// one that loses the race reports no change.
+func (s *service) track(ctx context.Context, candidate domain.Alert, excursion bool) (domain.Alert, bool, error) {
+	open, err := s.repository.GetOpen(ctx, candidate.Type, candidate.SectionID, candidate.ProductBatchID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return domain.Alert{}, false, err
+	}
+	hasOpen := err == nil
+
+	switch {
+	case excursion && !hasOpen:
+		candidate.Status = StatusOpen
+		candidate.OpenedAt = time.Now().UTC().Truncate(time.Second)
+		id, err := s.repository.Save(ctx, candidate)
+		if errors.Is(err, ErrAlreadyOpen) {
+			return domain.Alert{}, false, nil
+		}
+		if err != nil {
+			return domain.Alert{}, false, err
+		}
+		candidate.ID = id
+		s.notify(EventOpened, candidate)
+		return candidate, true, nil
+	case !excursion && hasOpen:
+		closedAt := time.Now().UTC().Truncate(time.Second)
+		err := s.repository.Close(ctx, open.ID, closedAt)
+		if errors.Is(err, ErrNotOpen) {
+			return domain.Alert{}, false, nil
+		}
+		if err != nil {
+			return domain.Alert{}, false, err
+		}
+		open.Status = StatusClosed
+		open.ClosedAt = &closedAt
+		s.notify(EventClosed, open)
+		return open, true, nil
+	}
+	return domain.Alert{}, false, nil
+}
+
+// notify delivers in the background so retries never hold the request that
+// triggered the evaluation.
+func (s *service) notify(event string, a domain.Alert) {
+	go func() {
+		if err := s.notifier.Notify(context.Background(), domain.AlertEvent{Event: event, Alert: a}); err != nil {
+			log.Printf("alert %d: %v", a.ID, err)
+		}
+	}()
+}
@@ -0,0 +1,208 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (r *repoM) GetAll(ctx context.Context, status string) ([]domain.Alert, error) {
+	args := r.Called(ctx, status)
+	return args.Get(0).([]domain.Alert), args.Error(1)
+}
+
+func (r *repoM) GetOpen(ctx context.Context, alertType string, sectionId, productBatchId int) (domain.Alert, error) {
+	args := r.Called(ctx, alertType, sectionId, productBatchId)
+	return args.Get(0).(domain.Alert), args.Error(1)
+}
+
+func (r *repoM) Save(ctx context.Context, a domain.Alert) (int, error) {
+	args := r.Called(ctx, a)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *repoM) Close(ctx context.Context, id int, closedAt time.Time) error {
+	args := r.Called(ctx, id, closedAt)
+	return args.Error(0)
+}
+
+func (r *repoM) GetSectionTemperature(ctx context.Context, sectionId int) (domain.SectionTemperature, error) {
+	args := r.Called(ctx, sectionId)
+	return args.Get(0).(domain.SectionTemperature), args.Error(1)
+}
+
+func (r *repoM) GetBatchTemperatures(ctx context.Context, sectionId int) ([]domain.BatchTemperature, error) {
+	args := r.Called(ctx, sectionId)
+	return args.Get(0).([]domain.BatchTemperature), args.Error(1)
+}
+
+// newReceiver starts a local webhook endpoint that forwards every verified event.
+func newReceiver(t *testing.T, secret string) (*httptest.Server, chan domain.AlertEvent) {
+	events := make(chan domain.AlertEvent, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign(secret, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event domain.AlertEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		events <- event
+	}))
+	return receiver, events
+}
+
+func waitEvent(t *testing.T, events chan domain.AlertEvent) domain.AlertEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("the webhook was not delivered")
+		return domain.AlertEvent{}
+	}
+}
+
+func TestEvaluateOpensSectionAlert(t *testing.T) {
+	receiver, events := newReceiver(t, "s3cr3t")
+	defer receiver.Close()
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 1).Return(domain.SectionTemperature{SectionID: 1, Temperature: -2, MinimumTemperature: 0}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeSection, 1, 0).Return(domain.Alert{}, sql.ErrNoRows)
+	repo.On("Save", mock.Anything, mock.Anything).Return(5, nil)
+	repo.On("GetBatchTemperatures", mock.Anything, 1).Return([]domain.BatchTemperature{}, nil)
+	s := NewService(repo, NewWebhookNotifier([]string{receiver.URL}, "s3cr3t", 3, time.Millisecond))
+
+	changed, err := s.EvaluateSection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, StatusOpen, changed[0].Status)
+
+	event := waitEvent(t, events)
+	assert.Equal(t, EventOpened, event.Event)
+	assert.Equal(t, 5, event.Alert.ID)
+	assert.Equal(t, AlertTypeSection, event.Alert.Type)
+}
+
+func TestEvaluateClosesRecoveredBatchAlert(t *testing.T) {
+	receiver, events := newReceiver(t, "s3cr3t")
+	defer receiver.Close()
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 1).Return(domain.SectionTemperature{SectionID: 1, Temperature: -18, MinimumTemperature: -20}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeSection, 1, 0).Return(domain.Alert{}, sql.ErrNoRows)
+	repo.On("GetBatchTemperatures", mock.Anything, 1).Return([]domain.BatchTemperature{
+		{BatchID: 3, BatchNumber: 111, MinimumTemperature: -25, RecomFreezTemp: -18},
+	}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeBatch, 1, 3).Return(domain.Alert{ID: 9, Type: AlertTypeBatch, SectionID: 1, ProductBatchID: 3, Status: StatusOpen}, nil)
+	repo.On("Close", mock.Anything, 9, mock.Anything).Return(nil)
+	s := NewService(repo, NewWebhookNotifier([]string{receiver.URL}, "s3cr3t", 3, time.Millisecond))
+
+	changed, err := s.EvaluateSection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, StatusClosed, changed[0].Status)
+	assert.NotNil(t, changed[0].ClosedAt)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+
+	event := waitEvent(t, events)
+	assert.Equal(t, EventClosed, event.Event)
+	assert.Equal(t, 9, event.Alert.ID)
+}
+
+func TestEvaluateKeepsOpenAlert(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 1).Return(domain.SectionTemperature{SectionID: 1, Temperature: -2, MinimumTemperature: 0}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeSection, 1, 0).Return(domain.Alert{ID: 4, Status: StatusOpen}, nil)
+	repo.On("GetBatchTemperatures", mock.Anything, 1).Return([]domain.BatchTemperature{}, nil)
+	s := NewService(repo, NewWebhookNotifier(nil, "", 1, 0))
+
+	changed, err := s.EvaluateSection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Close", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEvaluateBatchAboveRecommendedTemperature(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 1).Return(domain.SectionTemperature{SectionID: 1, Temperature: -17.5, MinimumTemperature: -20}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeSection, 1, 0).Return(domain.Alert{}, sql.ErrNoRows)
+	repo.On("GetBatchTemperatures", mock.Anything, 1).Return([]domain.BatchTemperature{
+		{BatchID: 3, BatchNumber: 111, MinimumTemperature: -25, RecomFreezTemp: -18},
+	}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeBatch, 1, 3).Return(domain.Alert{}, sql.ErrNoRows)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(a domain.Alert) bool { return a.ProductBatchID == 3 })).Return(6, nil)
+	s := NewService(repo, NewWebhookNotifier(nil, "", 1, 0))
+
+	changed, err := s.EvaluateSection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, AlertTypeBatch, changed[0].Type)
+	assert.Equal(t, 6, changed[0].ID)
+}
+
+func TestEvaluateSectionFractionalReadingBelowMinimum(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 1).Return(domain.SectionTemperature{SectionID: 1, Temperature: -20.4, MinimumTemperature: -20}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeSection, 1, 0).Return(domain.Alert{}, sql.ErrNoRows)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(a domain.Alert) bool { return a.Temperature == -20.4 })).Return(7, nil)
+	repo.On("GetBatchTemperatures", mock.Anything, 1).Return([]domain.BatchTemperature{}, nil)
+	s := NewService(repo, NewWebhookNotifier(nil, "", 1, 0))
+
+	changed, err := s.EvaluateSection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, changed, 1)
+	assert.Equal(t, AlertTypeSection, changed[0].Type)
+}
+
+func TestEvaluateConcurrentlyOpenedAlert(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 1).Return(domain.SectionTemperature{SectionID: 1, Temperature: -2, MinimumTemperature: 0}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeSection, 1, 0).Return(domain.Alert{}, sql.ErrNoRows)
+	repo.On("Save", mock.Anything, mock.Anything).Return(0, ErrAlreadyOpen)
+	repo.On("GetBatchTemperatures", mock.Anything, 1).Return([]domain.BatchTemperature{}, nil)
+	s := NewService(repo, NewWebhookNotifier(nil, "", 1, 0))
+
+	changed, err := s.EvaluateSection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestEvaluateConcurrentlyClosedAlert(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 1).Return(domain.SectionTemperature{SectionID: 1, Temperature: 2, MinimumTemperature: 0}, nil)
+	repo.On("GetOpen", mock.Anything, AlertTypeSection, 1, 0).Return(domain.Alert{ID: 4, Status: StatusOpen}, nil)
+	repo.On("Close", mock.Anything, 4, mock.Anything).Return(ErrNotOpen)
+	repo.On("GetBatchTemperatures", mock.Anything, 1).Return([]domain.BatchTemperature{}, nil)
+	s := NewService(repo, NewWebhookNotifier(nil, "", 1, 0))
+
+	changed, err := s.EvaluateSection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestEvaluateSectionNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetSectionTemperature", mock.Anything, 9).Return(domain.SectionTemperature{}, sql.ErrNoRows)
+	s := NewService(repo, NewWebhookNotifier(nil, "", 1, 0))
+	_, err := s.EvaluateSection(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrSectionNotFound)
+}
+
+func TestGetAllInvalidStatus(t *testing.T) {
+	s := NewService(new(repoM), NewWebhookNotifier(nil, "", 1, 0))
+	_, err := s.GetAll(context.Background(), "pending")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
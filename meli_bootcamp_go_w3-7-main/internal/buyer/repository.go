@@ -3,6 +3,7 @@ package buyer
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 )
@@ -15,8 +16,17 @@ type Repository interface {
 	Save(ctx context.Context, b domain.Buyer) (int, error)
 	Update(ctx context.Context, b domain.Buyer) error
 	Delete(ctx context.Context, id int) error
+	GetAllReportPurchaseOrders(ctx context.Context) ([]domain.ReportPurchaseOrders, error)
+	GetReportByBuyerIdPurchaseOrders(ctx context.Context, buyerId int) (domain.ReportPurchaseOrders, error)
 }
 
+// QueryReportPurchaseOrders counts the purchase orders of each buyer; buyers
+// without orders are reported with a count of 0.
+const QueryReportPurchaseOrders = `select b.id, b.card_number_id, b.first_name, b.last_name, count(p.id) as purchase_orders_count from buyers b
+left join purchase_orders p on b.id = p.buyer_id
+ %s 
+group by b.id, b.card_number_id, b.first_name, b.last_name;`
+
 type repository struct {
 	db *sql.DB
 }
@@ -127,3 +137,37 @@ func (r *repository) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (r *repository) GetAllReportPurchaseOrders(ctx context.Context) ([]domain.ReportPurchaseOrders, error) {
+	query := fmt.Sprintf(QueryReportPurchaseOrders, "")
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reportBuyers []domain.ReportPurchaseOrders
+
+	for rows.Next() {
+		b := domain.ReportPurchaseOrders{}
+		if err := rows.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName, &b.PurchaseOrdersCount); err != nil {
+			return nil, err
+		}
+		reportBuyers = append(reportBuyers, b)
+	}
+
+	return reportBuyers, rows.Err()
+}
+
+func (r *repository) GetReportByBuyerIdPurchaseOrders(ctx context.Context, buyerId int) (domain.ReportPurchaseOrders, error) {
+	query := fmt.Sprintf(QueryReportPurchaseOrders, "where b.id = ?")
+	row := r.db.QueryRowContext(ctx, query, buyerId)
+
+	b := domain.ReportPurchaseOrders{}
+	err := row.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName, &b.PurchaseOrdersCount)
+	if err != nil {
+		return domain.ReportPurchaseOrders{}, err
+	}
+
+	return b, nil
+}
@@ -0,0 +1,38 @@
+package buyer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReportByBuyerIdPurchaseOrdersWithoutOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "card_number_id", "first_name", "last_name", "purchase_orders_count"}).
+		AddRow(2, "402324", "Jane", "Doe", 0)
+	mock.ExpectQuery("left join purchase_orders p on b.id = p.buyer_id\\s+where b.id = \\?").WithArgs(2).WillReturnRows(rows)
+
+	repo := NewRepository(db)
+	report, err := repo.GetReportByBuyerIdPurchaseOrders(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ReportPurchaseOrders{ID: 2, CardNumberID: "402324", FirstName: "Jane", LastName: "Doe", PurchaseOrdersCount: 0}, report)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllReportPurchaseOrdersScanError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "card_number_id", "first_name", "last_name", "purchase_orders_count"}).
+		AddRow(1, "402323", "Jhon", "Doe", "many")
+	mock.ExpectQuery("left join purchase_orders").WillReturnRows(rows)
+
+	repo := NewRepository(db)
+	_, err = repo.GetAllReportPurchaseOrders(context.Background())
+	assert.Error(t, err)
+}
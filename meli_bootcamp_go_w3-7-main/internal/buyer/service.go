@@ -1,16 +1,104 @@
 package buyer
 
-import "errors"
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
 
 // Errors
 var (
-	ErrNotFound = errors.New("buyer not found")
+	ErrNotFound      = errors.New("buyer not found")
+	ErrAlreadyExists = errors.New("buyer already exists")
 )
 
-type Service interface{}
+type Service interface {
+	GetAll(ctx context.Context) ([]domain.Buyer, error)
+	Get(ctx context.Context, id int) (domain.Buyer, error)
+	Save(ctx context.Context, b domain.Buyer) (domain.Buyer, error)
+	Update(ctx context.Context, id int, b domain.Buyer) (domain.Buyer, error)
+	Delete(ctx context.Context, id int) error
+	GetAllReportPurchaseOrders(ctx context.Context) ([]domain.ReportPurchaseOrders, error)
+	GetReportByBuyerIdPurchaseOrders(ctx context.Context, buyerId int) (domain.ReportPurchaseOrders, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.Buyer, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Buyer, error) {
+	b, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Buyer{}, ErrNotFound
+		}
+		return domain.Buyer{}, err
+	}
+	return b, nil
+}
+
+func (s *service) Save(ctx context.Context, b domain.Buyer) (domain.Buyer, error) {
+	if s.repository.Exists(ctx, b.CardNumberID) {
+		return domain.Buyer{}, ErrAlreadyExists
+	}
+	id, err := s.repository.Save(ctx, b)
+	if err != nil {
+		return domain.Buyer{}, err
+	}
+	b.ID = id
+	return b, nil
+}
+
+func (s *service) Update(ctx context.Context, id int, b domain.Buyer) (domain.Buyer, error) {
+	buyerToUpdate, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.Buyer{}, err
+	}
+	updatedBuyer := updateFields(buyerToUpdate, b)
+	if err := s.repository.Update(ctx, updatedBuyer); err != nil {
+		return domain.Buyer{}, err
+	}
+	return updatedBuyer, nil
+}
 
-type service struct{}
+func (s *service) Delete(ctx context.Context, id int) error {
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *service) GetAllReportPurchaseOrders(ctx context.Context) ([]domain.ReportPurchaseOrders, error) {
+	return s.repository.GetAllReportPurchaseOrders(ctx)
+}
+
+func (s *service) GetReportByBuyerIdPurchaseOrders(ctx context.Context, buyerId int) (domain.ReportPurchaseOrders, error) {
+	report, err := s.repository.GetReportByBuyerIdPurchaseOrders(ctx, buyerId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ReportPurchaseOrders{}, ErrNotFound
+		}
+		return domain.ReportPurchaseOrders{}, err
+	}
+	return report, nil
+}
 
-func NewService() Service {
-	return &service{}
+// updateFields only overwrites the names, card_number_id can't be changed once the buyer is created.
+func updateFields(lastB domain.Buyer, newB domain.Buyer) domain.Buyer {
+	if newB.FirstName != lastB.FirstName && newB.FirstName != "" {
+		lastB.FirstName = newB.FirstName
+	}
+	if newB.LastName != lastB.LastName && newB.LastName != "" {
+		lastB.LastName = newB.LastName
+	}
+	return lastB
 }
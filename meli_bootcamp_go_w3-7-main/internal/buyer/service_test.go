@@ -0,0 +1,121 @@
+package buyer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (m *repoM) GetAll(ctx context.Context) ([]domain.Buyer, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Buyer), args.Error(1)
+}
+
+func (m *repoM) Get(ctx context.Context, id int) (domain.Buyer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Buyer), args.Error(1)
+}
+
+func (m *repoM) Exists(ctx context.Context, cardNumberID string) bool {
+	args := m.Called(ctx, cardNumberID)
+	return args.Bool(0)
+}
+
+func (m *repoM) Save(ctx context.Context, b domain.Buyer) (int, error) {
+	args := m.Called(ctx, b)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *repoM) Update(ctx context.Context, b domain.Buyer) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *repoM) Delete(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *repoM) GetAllReportPurchaseOrders(ctx context.Context) ([]domain.ReportPurchaseOrders, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.ReportPurchaseOrders), args.Error(1)
+}
+
+func (m *repoM) GetReportByBuyerIdPurchaseOrders(ctx context.Context, buyerId int) (domain.ReportPurchaseOrders, error) {
+	args := m.Called(ctx, buyerId)
+	return args.Get(0).(domain.ReportPurchaseOrders), args.Error(1)
+}
+
+func TestCreateOk(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "402323").Return(false)
+	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
+	s := NewService(repo)
+	b, err := s.Save(context.Background(), domain.Buyer{CardNumberID: "402323", FirstName: "Jhon", LastName: "Doe"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, b.ID)
+}
+
+func TestCreateConflict(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "402323").Return(true)
+	s := NewService(repo)
+	b, err := s.Save(context.Background(), domain.Buyer{CardNumberID: "402323"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	assert.Equal(t, domain.Buyer{}, b)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestFindByIdNonExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.Buyer{}, sql.ErrNoRows)
+	s := NewService(repo)
+	_, err := s.Get(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateExistent(t *testing.T) {
+	stored := domain.Buyer{ID: 1, CardNumberID: "402323", FirstName: "Jhon", LastName: "Doe"}
+	expected := domain.Buyer{ID: 1, CardNumberID: "402323", FirstName: "Jhon", LastName: "Smith"}
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(stored, nil)
+	repo.On("Update", mock.Anything, expected).Return(nil)
+	s := NewService(repo)
+	b, err := s.Update(context.Background(), 1, domain.Buyer{LastName: "Smith"})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, b)
+}
+
+func TestUpdateNonExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.Buyer{}, sql.ErrNoRows)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), 1, domain.Buyer{LastName: "Smith"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetReportByBuyerIdPurchaseOrdersOK(t *testing.T) {
+	expected := domain.ReportPurchaseOrders{ID: 1, CardNumberID: "402323", FirstName: "Jhon", LastName: "Doe", PurchaseOrdersCount: 3}
+	repo := new(repoM)
+	repo.On("GetReportByBuyerIdPurchaseOrders", mock.Anything, 1).Return(expected, nil)
+	s := NewService(repo)
+	report, err := s.GetReportByBuyerIdPurchaseOrders(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, report)
+}
+
+func TestGetReportByBuyerIdPurchaseOrdersNoResult(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetReportByBuyerIdPurchaseOrders", mock.Anything, 1).Return(domain.ReportPurchaseOrders{}, sql.ErrNoRows)
+	s := NewService(repo)
+	_, err := s.GetReportByBuyerIdPurchaseOrders(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/shipments"
 )
 
 // Repository encapsulates the storage of a carry.
@@ -18,6 +19,8 @@ type Repository interface {
 	Delete(ctx context.Context, id int) error
 	GetCarriesByLocality(ctx context.Context) ([]domain.CarriesReport, error)
 	GetCarriesByLocalityId(ctx context.Context, id int) (domain.CarriesReport, error)
+	ExistsLocality(ctx context.Context, localityId int) bool
+	GetEligible(ctx context.Context, localityId int) ([]domain.EligibleCarry, error)
 }
 
 type repository struct {
@@ -163,3 +166,33 @@ func (r *repository) GetCarriesByLocalityId(ctx context.Context, id int) (domain
 
 	return c, nil
 }
+
+func (r *repository) ExistsLocality(ctx context.Context, localityId int) bool {
+	query := "SELECT id FROM localities WHERE id=?;"
+	row := r.db.QueryRow(query, localityId)
+	err := row.Scan(&localityId)
+	return err == nil
+}
+
+// GetEligible returns the carries serving the locality, least loaded first.
+// A shipment counts as open until it has been delivered.
+func (r *repository) GetEligible(ctx context.Context, localityId int) ([]domain.EligibleCarry, error) {
+	query := "SELECT c.id, c.cid, c.company_name, c.address, c.telephone, c.locality_id, c.batch_number, COUNT(s.id) AS open_shipments FROM carries c LEFT JOIN shipments s ON s.carry_id = c.id AND s.status <> ? WHERE c.locality_id=? GROUP BY c.id, c.cid, c.company_name, c.address, c.telephone, c.locality_id, c.batch_number ORDER BY open_shipments ASC, c.id ASC;"
+	rows, err := r.db.QueryContext(ctx, query, shipments.StatusDelivered, localityId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carries := []domain.EligibleCarry{}
+
+	for rows.Next() {
+		c := domain.EligibleCarry{}
+		if err := rows.Scan(&c.ID, &c.CID, &c.CompanyName, &c.Address, &c.Telephone, &c.LocalityId, &c.BatchNumber, &c.OpenShipments); err != nil {
+			return nil, err
+		}
+		carries = append(carries, c)
+	}
+
+	return carries, rows.Err()
+}
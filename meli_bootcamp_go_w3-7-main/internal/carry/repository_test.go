@@ -81,3 +81,43 @@ func TestCreateCarryConflict(t *testing.T) {
 
 	assert.Equal(t, expectedMessageError, err.Error())
 }
+
+// eligibleCarriesQuery checks that only undelivered shipments count as load and that
+// the carries are ranked by that load, so the order is not just the one the mock returns.
+const eligibleCarriesQuery = "SELECT c.id, c.cid, .+ COUNT\\(s.id\\) AS open_shipments FROM carries c LEFT JOIN shipments s ON s.carry_id = c.id AND s.status <> \\? WHERE c.locality_id=\\? GROUP BY .+ ORDER BY open_shipments ASC, c.id ASC;"
+
+func TestGetEligibleCarriesOrderedByLoad(t *testing.T) {
+	db, mock := NewMock()
+	repo := &repository{db}
+	defer func() {
+		repo.Close()
+	}()
+	rows := sqlmock.NewRows([]string{"id", "cid", "company_name", "address", "telephone", "locality_id", "batch_number", "open_shipments"}).
+		AddRow(2, "c2", "company2", "Address2", "654321", 1, 2, 0).
+		AddRow(1, "c1", "company1", "Address1", "123456", 1, 1, 3)
+	mock.ExpectQuery(eligibleCarriesQuery).WithArgs("delivered", 1).WillReturnRows(rows)
+
+	carries, err := repo.GetEligible(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, carries, 2)
+	assert.Equal(t, 2, carries[0].ID)
+	assert.Equal(t, 0, carries[0].OpenShipments)
+	assert.Equal(t, 3, carries[1].OpenShipments)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEligibleCarriesEmpty(t *testing.T) {
+	db, mock := NewMock()
+	repo := &repository{db}
+	defer func() {
+		repo.Close()
+	}()
+	rows := sqlmock.NewRows([]string{"id", "cid", "company_name", "address", "telephone", "locality_id", "batch_number", "open_shipments"})
+	mock.ExpectQuery(eligibleCarriesQuery).WithArgs("delivered", 5).WillReturnRows(rows)
+
+	carries, err := repo.GetEligible(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Empty(t, carries)
+	assert.NotNil(t, carries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
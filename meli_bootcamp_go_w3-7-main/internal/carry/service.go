@@ -9,7 +9,8 @@ import (
 
 // Errors
 var (
-	ErrNotFound = errors.New("carry not found")
+	ErrNotFound         = errors.New("carry not found")
+	ErrLocalityNotFound = errors.New("locality not found")
 )
 
 type Service interface {
@@ -21,6 +22,7 @@ type Service interface {
 	Delete(ctx context.Context, id int) error
 	GetCarriesReportByLocality(ctx context.Context) ([]domain.CarriesReport, error)
 	GetCarryReportByLocalityId(ctx context.Context, id int) (domain.CarriesReport, error)
+	GetEligible(ctx context.Context, localityId int) ([]domain.EligibleCarry, error)
 }
 
 type service struct {
@@ -69,3 +71,12 @@ func (s *service) GetCarriesReportByLocality(ctx context.Context) ([]domain.Carr
 func (s *service) GetCarryReportByLocalityId(ctx context.Context, id int) (domain.CarriesReport, error) {
 	return s.repository.GetCarriesByLocalityId(ctx, id)
 }
+
+// GetEligible ranks the carries able to deliver to the locality by their
+// current open-shipment load.
+func (s *service) GetEligible(ctx context.Context, localityId int) ([]domain.EligibleCarry, error) {
+	if !s.repository.ExistsLocality(ctx, localityId) {
+		return nil, ErrLocalityNotFound
+	}
+	return s.repository.GetEligible(ctx, localityId)
+}
@@ -0,0 +1,45 @@
+package carry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	Repository
+	mock.Mock
+}
+
+func (m *repoM) ExistsLocality(ctx context.Context, localityId int) bool {
+	args := m.Called(ctx, localityId)
+	return args.Bool(0)
+}
+
+func (m *repoM) GetEligible(ctx context.Context, localityId int) ([]domain.EligibleCarry, error) {
+	args := m.Called(ctx, localityId)
+	return args.Get(0).([]domain.EligibleCarry), args.Error(1)
+}
+
+func TestGetEligibleLocalityNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsLocality", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	_, err := s.GetEligible(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrLocalityNotFound)
+	repo.AssertNotCalled(t, "GetEligible", mock.Anything, mock.Anything)
+}
+
+func TestGetEligibleOk(t *testing.T) {
+	expected := []domain.EligibleCarry{{Carry: domain.Carry{ID: 2, LocalityId: 1}, OpenShipments: 0}}
+	repo := new(repoM)
+	repo.On("ExistsLocality", mock.Anything, 1).Return(true)
+	repo.On("GetEligible", mock.Anything, 1).Return(expected, nil)
+	s := NewService(repo)
+	carries, err := s.GetEligible(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, carries)
+}
@@ -0,0 +1,132 @@
+package country
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Repository encapsulates the storage of a country.
+type Repository interface {
+	GetAll(ctx context.Context) ([]domain.Country, error)
+	Get(ctx context.Context, id int) (domain.Country, error)
+	Exists(ctx context.Context, countryName string) bool
+	HasProvinces(ctx context.Context, id int) bool
+	Save(ctx context.Context, c domain.Country) (int, error)
+	Update(ctx context.Context, c domain.Country) error
+	Delete(ctx context.Context, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.Country, error) {
+	query := "SELECT id, country_name FROM countries ORDER BY country_name;"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var countries []domain.Country
+
+	for rows.Next() {
+		c := domain.Country{}
+		_ = rows.Scan(&c.ID, &c.CountryName)
+		countries = append(countries, c)
+	}
+
+	return countries, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Country, error) {
+	query := "SELECT id, country_name FROM countries WHERE id=?;"
+	row := r.db.QueryRow(query, id)
+	c := domain.Country{}
+	err := row.Scan(&c.ID, &c.CountryName)
+	if err != nil {
+		return domain.Country{}, err
+	}
+
+	return c, nil
+}
+
+func (r *repository) Exists(ctx context.Context, countryName string) bool {
+	query := "SELECT country_name FROM countries WHERE country_name=?;"
+	row := r.db.QueryRow(query, countryName)
+	err := row.Scan(&countryName)
+	return err == nil
+}
+
+func (r *repository) HasProvinces(ctx context.Context, id int) bool {
+	query := "SELECT id FROM provinces WHERE country_id=? LIMIT 1;"
+	row := r.db.QueryRow(query, id)
+	err := row.Scan(&id)
+	return err == nil
+}
+
+func (r *repository) Save(ctx context.Context, c domain.Country) (int, error) {
+	query := "INSERT INTO countries (country_name) VALUES (?);"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(c.CountryName)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) Update(ctx context.Context, c domain.Country) error {
+	query := "UPDATE countries SET country_name=? WHERE id=?;"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(c.CountryName, c.ID)
+	return err
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM countries WHERE id=?;"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
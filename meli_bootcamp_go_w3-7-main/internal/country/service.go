@@ -0,0 +1,86 @@
+package country
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Errors
+var (
+	ErrNotFound      = errors.New("country not found")
+	ErrAlreadyExists = errors.New("country already exists")
+	ErrHasProvinces  = errors.New("country has provinces")
+)
+
+type Service interface {
+	GetAll(ctx context.Context) ([]domain.Country, error)
+	Get(ctx context.Context, id int) (domain.Country, error)
+	Save(ctx context.Context, c domain.Country) (domain.Country, error)
+	Update(ctx context.Context, c domain.Country) (domain.Country, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.Country, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Country, error) {
+	c, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Country{}, ErrNotFound
+		}
+		return domain.Country{}, err
+	}
+	return c, nil
+}
+
+func (s *service) Save(ctx context.Context, c domain.Country) (domain.Country, error) {
+	if s.repository.Exists(ctx, c.CountryName) {
+		return domain.Country{}, ErrAlreadyExists
+	}
+	id, err := s.repository.Save(ctx, c)
+	if err != nil {
+		return domain.Country{}, err
+	}
+	c.ID = id
+	return c, nil
+}
+
+func (s *service) Update(ctx context.Context, c domain.Country) (domain.Country, error) {
+	last, err := s.Get(ctx, c.ID)
+	if err != nil {
+		return domain.Country{}, err
+	}
+	if c.CountryName != last.CountryName && s.repository.Exists(ctx, c.CountryName) {
+		return domain.Country{}, ErrAlreadyExists
+	}
+	if err := s.repository.Update(ctx, c); err != nil {
+		return domain.Country{}, err
+	}
+	return c, nil
+}
+
+// Delete refuses to remove a country while provinces still reference it.
+func (s *service) Delete(ctx context.Context, id int) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if s.repository.HasProvinces(ctx, id) {
+		return ErrHasProvinces
+	}
+	return s.repository.Delete(ctx, id)
+}
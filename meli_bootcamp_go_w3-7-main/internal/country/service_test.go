@@ -0,0 +1,95 @@
+package country
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (r *repoM) GetAll(ctx context.Context) ([]domain.Country, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]domain.Country), args.Error(1)
+}
+
+func (r *repoM) Get(ctx context.Context, id int) (domain.Country, error) {
+	args := r.Called(ctx, id)
+	return args.Get(0).(domain.Country), args.Error(1)
+}
+
+func (r *repoM) Exists(ctx context.Context, countryName string) bool {
+	args := r.Called(ctx, countryName)
+	return args.Bool(0)
+}
+
+func (r *repoM) HasProvinces(ctx context.Context, id int) bool {
+	args := r.Called(ctx, id)
+	return args.Bool(0)
+}
+
+func (r *repoM) Save(ctx context.Context, c domain.Country) (int, error) {
+	args := r.Called(ctx, c)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *repoM) Update(ctx context.Context, c domain.Country) error {
+	args := r.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (r *repoM) Delete(ctx context.Context, id int) error {
+	args := r.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateOk(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "Argentina").Return(false)
+	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
+	s := NewService(repo)
+	result, err := s.Save(context.Background(), domain.Country{CountryName: "Argentina"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Country{ID: 1, CountryName: "Argentina"}, result)
+}
+
+func TestCreateConflict(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "Argentina").Return(true)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Country{CountryName: "Argentina"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestGetNonExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 9).Return(domain.Country{}, sql.ErrNoRows)
+	s := NewService(repo)
+	_, err := s.Get(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateConflict(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.Country{ID: 1, CountryName: "Argentina"}, nil)
+	repo.On("Exists", mock.Anything, "Chile").Return(true)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Country{ID: 1, CountryName: "Chile"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestDeleteWithProvinces(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.Country{ID: 1, CountryName: "Argentina"}, nil)
+	repo.On("HasProvinces", mock.Anything, 1).Return(true)
+	s := NewService(repo)
+	err := s.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrHasProvinces)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
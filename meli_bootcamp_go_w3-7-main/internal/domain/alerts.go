@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+type Alert struct {
+	ID             int        `json:"id"`
+	Type           string     `json:"type"`
+	SectionID      int        `json:"section_id"`
+	ProductBatchID int        `json:"product_batch_id,omitempty"`
+	Temperature    float32    `json:"temperature"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	OpenedAt       time.Time  `json:"opened_at"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+}
+
+type AlertEvent struct {
+	Event string `json:"event"`
+	Alert Alert  `json:"alert"`
+}
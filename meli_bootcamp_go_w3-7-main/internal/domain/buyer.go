@@ -6,3 +6,11 @@ type Buyer struct {
 	FirstName    string `json:"first_name"`
 	LastName     string `json:"last_name"`
 }
+
+type ReportPurchaseOrders struct {
+	ID                  int    `json:"id"`
+	CardNumberID        string `json:"card_number_id"`
+	FirstName           string `json:"first_name"`
+	LastName            string `json:"last_name"`
+	PurchaseOrdersCount int    `json:"purchase_orders_count"`
+}
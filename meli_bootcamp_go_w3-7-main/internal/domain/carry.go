@@ -25,3 +25,10 @@ type NewCarry struct {
 	LocalityId  int    `json:"locality_id" binding:"required"`
 	BatchNumber int    `json:"batch_number" binding:"required"`
 }
+
+// EligibleCarry is a carry serving a locality together with the number of
+// shipments it still has to deliver.
+type EligibleCarry struct {
+	Carry
+	OpenShipments int `json:"open_shipments"`
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Employee struct {
 	ID           int    `json:"id"`
 	CardNumberID string `json:"card_number_id"`
@@ -9,10 +11,30 @@ type Employee struct {
 }
 
 type ReportInboundOrders struct {
-	ID                 int    `json:"id"`
-	CardNumberID       string `json:"card_number_id"`
-	FirstName          string `json:"first_name"`
-	LastName           string `json:"last_name"`
-	WarehouseID        int    `json:"warehouse_id"`
+	ID                 int                      `json:"id"`
+	CardNumberID       string                   `json:"card_number_id"`
+	FirstName          string                   `json:"first_name"`
+	LastName           string                   `json:"last_name"`
+	WarehouseID        int                      `json:"warehouse_id"`
+	InboundOrdersCount int                      `json:"inbound_orders_count"`
+	Days               []ReportInboundOrdersDay `json:"days"`
+}
+
+type ReportInboundOrdersDay struct {
+	Date               string `json:"date"`
 	InboundOrdersCount int    `json:"inbound_orders_count"`
 }
+
+type ReportInboundOrdersFilter struct {
+	WarehouseID int
+	From        time.Time
+	To          time.Time
+}
+
+type EmployeeAssignment struct {
+	ID          int        `json:"id"`
+	EmployeeID  int        `json:"employee_id"`
+	WarehouseID int        `json:"warehouse_id"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+}
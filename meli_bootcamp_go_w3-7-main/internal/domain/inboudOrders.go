@@ -9,4 +9,22 @@ type InboudOrders struct {
 	EmployeeId     int       `json:"employee_id"`
 	ProductBatchId int       `json:"product_batch_id"`
 	WarehouseID    int       `json:"warehouse_id"`
+	Quantity       int       `json:"quantity"`
+}
+
+type InboudOrdersFilter struct {
+	EmployeeId  int
+	WarehouseID int
+	From        time.Time
+	To          time.Time
+	Page        int
+	Limit       int
+	Order       string
+}
+
+type InboudOrdersPage struct {
+	Data  []InboudOrders `json:"data"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Total int            `json:"total"`
 }
@@ -4,6 +4,33 @@ type Locality struct {
 	ID           int    `json:"id"`
 	ZipCode      string `json:"zip_code"`
 	LocalityName string `json:"locality_name"`
-	ProvinceName string `json:"province_name"`
-	CountryName  string `json:"country_name"`
+	ProvinceID   int    `json:"province_id"`
+	ProvinceName string `json:"province_name,omitempty"`
+	CountryID    int    `json:"country_id"`
+	CountryName  string `json:"country_name,omitempty"`
+}
+
+type LocalityReport struct {
+	LocalityID   int    `json:"locality_id"`
+	ZipCode      string `json:"zip_code"`
+	LocalityName string `json:"locality_name"`
+	SellersCount int    `json:"sellers_count"`
+	CarriesCount int    `json:"carries_count"`
+}
+
+// LocalityImportRow is the outcome of one row of a locality import. Row is
+// the position of the record in the file, header excluded, starting at 1.
+type LocalityImportRow struct {
+	Row     int    `json:"row"`
+	ZipCode string `json:"zip_code,omitempty"`
+	Status  string `json:"status"`
+	ID      int    `json:"id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type LocalityImportReport struct {
+	Created int                 `json:"created"`
+	Skipped int                 `json:"skipped"`
+	Invalid int                 `json:"invalid"`
+	Rows    []LocalityImportRow `json:"rows"`
 }
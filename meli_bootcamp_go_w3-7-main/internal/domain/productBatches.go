@@ -14,3 +14,62 @@ type ProductBatches struct {
 	ProductId          int     `json:"product_id"`
 	SectionId          int     `json:"section_id"`
 }
+
+type PickLine struct {
+	ProductBatchID int    `json:"product_batch_id"`
+	BatchNumber    int    `json:"batch_number"`
+	SectionID      int    `json:"section_id"`
+	DueDate        string `json:"due_date"`
+	Quantity       int    `json:"quantity"`
+}
+
+type PickRequest struct {
+	ProductID         int    `json:"product_id"`
+	Quantity          int    `json:"quantity"`
+	WarehouseID       int    `json:"warehouse_id"`
+	EmployeeID        int    `json:"employee_id"`
+	ReferenceDocument string `json:"reference_document"`
+}
+
+type PickList struct {
+	ProductID   int        `json:"product_id"`
+	WarehouseID int        `json:"warehouse_id,omitempty"`
+	Quantity    int        `json:"quantity"`
+	Lines       []PickLine `json:"lines"`
+}
+
+type ExpiringBatch struct {
+	ProductBatchID  int    `json:"product_batch_id"`
+	BatchNumber     int    `json:"batch_number"`
+	ProductID       int    `json:"product_id"`
+	SectionID       int    `json:"section_id"`
+	SectionNumber   int    `json:"section_number"`
+	WarehouseID     int    `json:"warehouse_id"`
+	CurrentQuantity int    `json:"current_quantity"`
+	DueDate         string `json:"due_date"`
+	DaysLeft        int    `json:"days_left"`
+	Blocked         bool   `json:"blocked"`
+}
+
+type ExpiringSection struct {
+	SectionID     int             `json:"section_id"`
+	SectionNumber int             `json:"section_number"`
+	Batches       []ExpiringBatch `json:"batches"`
+}
+
+type ExpiringWarehouse struct {
+	WarehouseID int               `json:"warehouse_id"`
+	Sections    []ExpiringSection `json:"sections"`
+}
+
+type ExpiringReport struct {
+	Days       int                 `json:"days"`
+	Expired    []ExpiringBatch     `json:"expired"`
+	Warehouses []ExpiringWarehouse `json:"warehouses"`
+}
+
+type ReportProducts struct {
+	SectionID     int `json:"section_id"`
+	SectionNumber int `json:"section_number"`
+	ProductsCount int `json:"products_count"`
+}
@@ -0,0 +1,6 @@
+package domain
+
+type ProductType struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+}
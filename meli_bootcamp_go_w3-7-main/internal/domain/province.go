@@ -0,0 +1,7 @@
+package domain
+
+type Province struct {
+	ID           int    `json:"id"`
+	ProvinceName string `json:"province_name"`
+	CountryID    int    `json:"country_id"`
+}
@@ -11,3 +11,23 @@ type Section struct {
 	WarehouseID        int `json:"warehouse_id"`
 	ProductTypeID      int `json:"product_type_id"`
 }
+
+type ProductTemperatureRange struct {
+	BatchNumber        int     `json:"batch_number"`
+	ProductID          int     `json:"product_id"`
+	MinimumTemperature float32 `json:"minimum_temperature"`
+	RecomFreezTemp     float32 `json:"recommended_freezing_temperature"`
+}
+
+type SectionTemperature struct {
+	SectionID          int     `json:"section_id"`
+	Temperature        float32 `json:"temperature"`
+	MinimumTemperature int     `json:"minimum_temperature"`
+}
+
+type BatchTemperature struct {
+	BatchID            int     `json:"product_batch_id"`
+	BatchNumber        int     `json:"batch_number"`
+	MinimumTemperature float32 `json:"minimum_temperature"`
+	RecomFreezTemp     float32 `json:"recommended_freezing_temperature"`
+}
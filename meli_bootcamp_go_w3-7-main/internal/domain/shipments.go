@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type Shipment struct {
+	ID              int             `json:"id"`
+	TrackingCode    string          `json:"tracking_code"`
+	CarryID         int             `json:"carry_id"`
+	PurchaseOrderID int             `json:"purchase_order_id,omitempty"`
+	ProductBatchIDs []int           `json:"product_batch_ids,omitempty"`
+	Status          string          `json:"status"`
+	CreatedAt       time.Time       `json:"created_at"`
+	Events          []ShipmentEvent `json:"events"`
+}
+
+type ShipmentEvent struct {
+	ID         int       `json:"id"`
+	Type       string    `json:"type"`
+	Location   string    `json:"location,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type StockMovement struct {
+	ID                int       `json:"id"`
+	Type              string    `json:"type"`
+	ProductBatchID    int       `json:"product_batch_id,omitempty"`
+	SectionID         int       `json:"section_id"`
+	Quantity          int       `json:"quantity"`
+	EmployeeID        int       `json:"employee_id,omitempty"`
+	ReferenceDocument string    `json:"reference_document,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type StockMovementFilter struct {
+	Type           string
+	ProductBatchID int
+	SectionID      int
+	EmployeeID     int
+	From           time.Time
+	To             time.Time
+	Page           int
+	Limit          int
+}
+
+type StockMovementsPage struct {
+	Data  []StockMovement `json:"data"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+	Total int             `json:"total"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+type TemperatureReading struct {
+	ID          int       `json:"id"`
+	SectionID   int       `json:"section_id"`
+	Temperature float32   `json:"temperature"`
+	ReadAt      time.Time `json:"read_at"`
+}
+
+type TemperatureAggregate struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Min      float32   `json:"min"`
+	Max      float32   `json:"max"`
+	Avg      float32   `json:"avg"`
+	Readings int       `json:"readings"`
+}
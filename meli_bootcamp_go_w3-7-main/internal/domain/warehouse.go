@@ -16,3 +16,39 @@ type newWarehouse struct {
 	MinimumCapacity    int    `json:"minimum_capacity"  binding:"required"`
 	MinimumTemperature int    `json:"minimum_temperature"  binding:"required"`
 }
+
+type SectionOccupancy struct {
+	SectionID       int     `json:"section_id"`
+	SectionNumber   int     `json:"section_number"`
+	CurrentCapacity int     `json:"current_capacity"`
+	MinimumCapacity int     `json:"minimum_capacity"`
+	MaximumCapacity int     `json:"maximum_capacity"`
+	Utilization     float64 `json:"utilization"`
+	BelowMinimum    bool    `json:"below_minimum"`
+	NearlyFull      bool    `json:"nearly_full"`
+}
+
+type WarehouseOccupancy struct {
+	WarehouseID          int                `json:"warehouse_id"`
+	WarehouseCode        string             `json:"warehouse_code"`
+	CurrentCapacity      int                `json:"current_capacity"`
+	MinimumCapacity      int                `json:"minimum_capacity"`
+	MaximumCapacity      int                `json:"maximum_capacity"`
+	Utilization          float64            `json:"utilization"`
+	BelowMinimum         bool               `json:"below_minimum"`
+	Sections             []SectionOccupancy `json:"sections"`
+	SectionsBelowMinimum []int              `json:"sections_below_minimum"`
+	SectionsNearlyFull   []int              `json:"sections_nearly_full"`
+}
+
+type WarehouseDependents struct {
+	SectionIDs          []int `json:"section_ids"`
+	EmployeeIDs         []int `json:"employee_ids"`
+	ProductBatchIDs     []int `json:"product_batch_ids"`
+	InboundOrderIDs     []int `json:"inbound_order_ids"`
+	TemperatureReadings int   `json:"temperature_readings"`
+	Alerts              int   `json:"alerts"`
+	Stock               int   `json:"stock"`
+	StockMovements      int   `json:"stock_movements"`
+	ShipmentBatches     int   `json:"shipment_batches"`
+}
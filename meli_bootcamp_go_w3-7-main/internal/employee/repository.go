@@ -3,9 +3,10 @@ package employee
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/store"
 )
 
 // Repository encapsulates the storage of a employee.
@@ -16,14 +17,22 @@ type Repository interface {
 	Save(ctx context.Context, e domain.Employee) (int, error)
 	Update(ctx context.Context, e domain.Employee) error
 	Delete(ctx context.Context, id int) error
-	GetAllReportInboundOrders() ([]domain.ReportInboundOrders, error)
-	GetReportByEmployeeIdInboundOrders(employeeId int) (domain.ReportInboundOrders, error)
+	ExistsWarehouse(ctx context.Context, warehouseId int) bool
+	GetAssignments(ctx context.Context, employeeId int) ([]domain.EmployeeAssignment, error)
+	GetByWarehouse(ctx context.Context, warehouseId int, at time.Time) ([]domain.Employee, error)
+	GetAllReportInboundOrders(ctx context.Context, filter domain.ReportInboundOrdersFilter) ([]domain.ReportInboundOrders, error)
+	GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int, filter domain.ReportInboundOrdersFilter) (domain.ReportInboundOrders, error)
 }
 
-const QueryReportInboundOrders = `select e.id, e.card_number_id,e.first_name,e.last_name,e.warehouse_id,count(i.id) as inbound_orders_count from employees e
-inner join inbound_orders i on e.id = i.employee_id
- %s 
-group by e.id;`
+// The date range is applied in the join so that employees without orders in
+// the period are still listed with a count of zero.
+const QueryReportInboundOrders = `SELECT e.id, e.card_number_id, e.first_name, e.last_name, e.warehouse_id, COUNT(i.id) AS inbound_orders_count FROM employees e
+LEFT JOIN inbound_orders i ON e.id = i.employee_id`
+
+const QueryReportInboundOrdersDays = `SELECT e.id, DATE(i.order_date) AS order_day, COUNT(i.id) AS inbound_orders_count FROM employees e
+INNER JOIN inbound_orders i ON e.id = i.employee_id`
+
+const dateTimeLayout = "2006-01-02 15:04:05"
 
 type repository struct {
 	db *sql.DB
@@ -72,14 +81,16 @@ func (r *repository) Exists(ctx context.Context, cardNumberID string) bool {
 	return err == nil
 }
 
+// Save inserts the employee and opens its first warehouse assignment.
 func (r *repository) Save(ctx context.Context, e domain.Employee) (int, error) {
-	query := "INSERT INTO employees(card_number_id,first_name,last_name,warehouse_id) VALUES (?,?,?,?)"
-	stmt, err := r.db.Prepare(query)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
+	defer tx.Rollback()
 
-	res, err := stmt.Exec(&e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID)
+	query := "INSERT INTO employees(card_number_id,first_name,last_name,warehouse_id) VALUES (?,?,?,?)"
+	res, err := tx.ExecContext(ctx, query, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID)
 	if err != nil {
 		return 0, err
 	}
@@ -89,41 +100,62 @@ func (r *repository) Save(ctx context.Context, e domain.Employee) (int, error) {
 		return 0, err
 	}
 
+	if err := openAssignment(ctx, tx, int(id), e.WarehouseID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return int(id), nil
 }
 
+// Update saves the employee and, when the warehouse changes, closes the open
+// assignment and opens a new one in the same transaction.
 func (r *repository) Update(ctx context.Context, e domain.Employee) error {
-	query := "UPDATE employees SET first_name=?, last_name=?, warehouse_id=?  WHERE id=?"
-	stmt, err := r.db.Prepare(query)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-
-	res, err := stmt.Exec(&e.FirstName, &e.LastName, &e.WarehouseID, &e.ID)
-	if err != nil {
+	defer tx.Rollback()
+
+	var warehouseId int
+	query := "SELECT warehouse_id FROM employees WHERE id=? FOR UPDATE;"
+	if err := tx.QueryRowContext(ctx, query, e.ID).Scan(&warehouseId); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
 		return err
 	}
 
-	affect, err := res.RowsAffected()
-	if err != nil {
+	query = "UPDATE employees SET first_name=?, last_name=?, warehouse_id=?  WHERE id=?"
+	if _, err := tx.ExecContext(ctx, query, &e.FirstName, &e.LastName, &e.WarehouseID, &e.ID); err != nil {
 		return err
 	}
 
-	if affect < 1 {
-		return ErrNotFound
+	if warehouseId != e.WarehouseID {
+		if err := closeAssignment(ctx, tx, e.ID); err != nil {
+			return err
+		}
+		if err := openAssignment(ctx, tx, e.ID, e.WarehouseID); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return tx.Commit()
 }
 
+// Delete removes the employee and closes its open assignment, keeping the history.
 func (r *repository) Delete(ctx context.Context, id int) error {
-	query := "DELETE FROM employees WHERE id=?"
-	stmt, err := r.db.Prepare(query)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	res, err := stmt.Exec(id)
+	query := "DELETE FROM employees WHERE id=?"
+	res, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -137,36 +169,186 @@ func (r *repository) Delete(ctx context.Context, id int) error {
 		return ErrNotFound
 	}
 
-	return nil
+	if err := closeAssignment(ctx, tx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *repository) GetAllReportInboundOrders() ([]domain.ReportInboundOrders, error) {
-	query := fmt.Sprintf(QueryReportInboundOrders, "")
-	rows, err := r.db.Query(query)
+func (r *repository) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	return store.ExistsWarehouse(ctx, r.db, warehouseId)
+}
+
+func (r *repository) GetAssignments(ctx context.Context, employeeId int) ([]domain.EmployeeAssignment, error) {
+	query := "SELECT id, employee_id, warehouse_id, start_date, end_date FROM employee_assignments WHERE employee_id=? ORDER BY start_date, id;"
+	rows, err := r.db.QueryContext(ctx, query, employeeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []domain.EmployeeAssignment{}
+
+	for rows.Next() {
+		a := domain.EmployeeAssignment{}
+		var startDate string
+		var endDate sql.NullString
+		if err := rows.Scan(&a.ID, &a.EmployeeID, &a.WarehouseID, &startDate, &endDate); err != nil {
+			return nil, err
+		}
+		if a.StartDate, err = time.Parse(dateTimeLayout, startDate); err != nil {
+			return nil, err
+		}
+		if endDate.Valid {
+			t, err := time.Parse(dateTimeLayout, endDate.String)
+			if err != nil {
+				return nil, err
+			}
+			a.EndDate = &t
+		}
+		assignments = append(assignments, a)
+	}
+
+	return assignments, rows.Err()
+}
+
+// GetByWarehouse lists the employees currently working in the warehouse or,
+// when at is not zero, the ones assigned to it at that moment, including the
+// ones deleted since, which only keep their id.
+func (r *repository) GetByWarehouse(ctx context.Context, warehouseId int, at time.Time) ([]domain.Employee, error) {
+	query := "SELECT id, card_number_id, first_name, last_name, warehouse_id FROM employees WHERE warehouse_id=? ORDER BY id;"
+	args := []interface{}{warehouseId}
+	if !at.IsZero() {
+		query = `SELECT a.employee_id, COALESCE(e.card_number_id, ''), COALESCE(e.first_name, ''), COALESCE(e.last_name, ''), a.warehouse_id FROM employee_assignments a
+LEFT JOIN employees e ON e.id = a.employee_id
+WHERE a.warehouse_id=? AND a.start_date <= ? AND (a.end_date IS NULL OR a.end_date > ?) ORDER BY a.employee_id;`
+		date := at.UTC().Format(dateTimeLayout)
+		args = append(args, date, date)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	var repostEmployees []domain.ReportInboundOrders
+	employees := []domain.Employee{}
 
 	for rows.Next() {
-		i := domain.ReportInboundOrders{}
-		_ = rows.Scan(&i.ID, &i.CardNumberID, &i.FirstName, &i.LastName, &i.WarehouseID, &i.InboundOrdersCount)
-		repostEmployees = append(repostEmployees, i)
+		e := domain.Employee{}
+		if err := rows.Scan(&e.ID, &e.CardNumberID, &e.FirstName, &e.LastName, &e.WarehouseID); err != nil {
+			return nil, err
+		}
+		employees = append(employees, e)
 	}
 
-	return repostEmployees, nil
+	return employees, rows.Err()
 }
 
-func (r *repository) GetReportByEmployeeIdInboundOrders(employeeId int) (domain.ReportInboundOrders, error) {
-	query := fmt.Sprintf(QueryReportInboundOrders, "where e.id = ?")
-	row := r.db.QueryRow(query, employeeId)
+func openAssignment(ctx context.Context, tx *sql.Tx, employeeId, warehouseId int) error {
+	query := "INSERT INTO employee_assignments (employee_id, warehouse_id, start_date) VALUES (?, ?, UTC_TIMESTAMP());"
+	_, err := tx.ExecContext(ctx, query, employeeId, warehouseId)
+	return err
+}
 
-	i := domain.ReportInboundOrders{}
-	err := row.Scan(&i.ID, &i.CardNumberID, &i.FirstName, &i.LastName, &i.WarehouseID, &i.InboundOrdersCount)
+func closeAssignment(ctx context.Context, tx *sql.Tx, employeeId int) error {
+	query := "UPDATE employee_assignments SET end_date=UTC_TIMESTAMP() WHERE employee_id=? AND end_date IS NULL;"
+	_, err := tx.ExecContext(ctx, query, employeeId)
+	return err
+}
+
+func (r *repository) GetAllReportInboundOrders(ctx context.Context, filter domain.ReportInboundOrdersFilter) ([]domain.ReportInboundOrders, error) {
+	return r.reportInboundOrders(ctx, 0, filter)
+}
+
+func (r *repository) GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int, filter domain.ReportInboundOrdersFilter) (domain.ReportInboundOrders, error) {
+	reports, err := r.reportInboundOrders(ctx, employeeId, filter)
 	if err != nil {
 		return domain.ReportInboundOrders{}, err
 	}
+	if len(reports) == 0 {
+		return domain.ReportInboundOrders{}, sql.ErrNoRows
+	}
+	return reports[0], nil
+}
+
+// reportInboundOrders counts the inbound orders of every employee matching the
+// filter (or only employeeId when it is not zero) and adds the per-day breakdown.
+func (r *repository) reportInboundOrders(ctx context.Context, employeeId int, filter domain.ReportInboundOrdersFilter) ([]domain.ReportInboundOrders, error) {
+	on, where, args := reportInboundOrdersConditions(employeeId, filter)
+	query := QueryReportInboundOrders + on + where + " GROUP BY e.id ORDER BY e.id;"
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reportEmployees []domain.ReportInboundOrders
+	byEmployee := map[int]int{}
+
+	for rows.Next() {
+		i := domain.ReportInboundOrders{Days: []domain.ReportInboundOrdersDay{}}
+		if err := rows.Scan(&i.ID, &i.CardNumberID, &i.FirstName, &i.LastName, &i.WarehouseID, &i.InboundOrdersCount); err != nil {
+			return nil, err
+		}
+		byEmployee[i.ID] = len(reportEmployees)
+		reportEmployees = append(reportEmployees, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return i, nil
+	query = QueryReportInboundOrdersDays + on + where + " GROUP BY e.id, order_day ORDER BY e.id, order_day;"
+	dayRows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer dayRows.Close()
+
+	for dayRows.Next() {
+		var id int
+		d := domain.ReportInboundOrdersDay{}
+		if err := dayRows.Scan(&id, &d.Date, &d.InboundOrdersCount); err != nil {
+			return nil, err
+		}
+		if idx, ok := byEmployee[id]; ok {
+			reportEmployees[idx].Days = append(reportEmployees[idx].Days, d)
+		}
+	}
+	if err := dayRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reportEmployees, nil
+}
+
+// reportInboundOrdersConditions returns the join conditions on the orders, the
+// where clause on the employees and their arguments, in that order. The warehouse
+// filter counts the orders received in that warehouse, whoever received them, and
+// keeps the employees currently working there even when they have none.
+func reportInboundOrdersConditions(employeeId int, filter domain.ReportInboundOrdersFilter) (string, string, []interface{}) {
+	var args []interface{}
+	on := ""
+	if !filter.From.IsZero() {
+		on += " AND i.order_date >= ?"
+		args = append(args, filter.From.UTC().Format(dateTimeLayout))
+	}
+	if !filter.To.IsZero() {
+		on += " AND i.order_date < ?"
+		args = append(args, filter.To.UTC().Format(dateTimeLayout))
+	}
+	if filter.WarehouseID != 0 {
+		on += " AND i.warehouse_id=?"
+		args = append(args, filter.WarehouseID)
+	}
+	where := " WHERE 1=1"
+	if employeeId != 0 {
+		where += " AND e.id=?"
+		args = append(args, employeeId)
+	}
+	if filter.WarehouseID != 0 {
+		where += " AND (e.warehouse_id=? OR i.id IS NOT NULL)"
+		args = append(args, filter.WarehouseID)
+	}
+	return on, where, args
 }
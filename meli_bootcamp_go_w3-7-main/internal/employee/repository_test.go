@@ -5,12 +5,21 @@ import (
 	"fmt"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
+func TestReportInboundOrdersConditionsFilterOrderWarehouse(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	on, where, args := reportInboundOrdersConditions(3, domain.ReportInboundOrdersFilter{From: from, WarehouseID: 2})
+	assert.Equal(t, " AND i.order_date >= ? AND i.warehouse_id=?", on)
+	assert.Equal(t, " WHERE 1=1 AND e.id=? AND (e.warehouse_id=? OR i.id IS NOT NULL)", where)
+	assert.Equal(t, []interface{}{"2022-01-01 00:00:00", 2, 3, 2}, args)
+}
+
 func TestRepository(t *testing.T) {
 	db, err := utils.InitDB()
 	assert.NoError(t, err)
@@ -63,10 +72,10 @@ func TestRepository(t *testing.T) {
 	assert.NoError(t, err)
 	err = repo.Delete(ctx, 2)
 	assert.Error(t, err)
-	resultList, err := repo.GetAllReportInboundOrders()
+	resultList, err := repo.GetAllReportInboundOrders(ctx, domain.ReportInboundOrdersFilter{})
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResultReportGetAll, resultList)
-	resultReportByEmployees, _ := repo.GetReportByEmployeeIdInboundOrders(1)
+	resultReportByEmployees, _ := repo.GetReportByEmployeeIdInboundOrders(ctx, 1, domain.ReportInboundOrdersFilter{})
 	assert.Equal(t, expectedResultReportByEmployees, resultReportByEmployees)
 
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 )
@@ -12,6 +13,8 @@ import (
 var (
 	ErrNotFound           = errors.New("employee not found")
 	ErrorEmployeesNoExist = errors.New("The employee doesn't exist")
+	ErrInvalidRange       = errors.New("the from date must be before the to date")
+	ErrWarehouseNotFound  = errors.New("warehouse not found")
 )
 
 type Service interface {
@@ -20,8 +23,10 @@ type Service interface {
 	Save(ctx context.Context, e domain.Employee) (domain.Employee, error)
 	Update(ctx context.Context, id int, e domain.Employee) (domain.Employee, error)
 	Delete(ctx context.Context, id int) error
-	GetAllReportInboundOrders(ctx context.Context) ([]domain.ReportInboundOrders, error)
-	GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int) (domain.ReportInboundOrders, error)
+	GetAssignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error)
+	GetByWarehouse(ctx context.Context, warehouseId int, at time.Time) ([]domain.Employee, error)
+	GetAllReportInboundOrders(ctx context.Context, filter domain.ReportInboundOrdersFilter) ([]domain.ReportInboundOrders, error)
+	GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int, filter domain.ReportInboundOrdersFilter) (domain.ReportInboundOrders, error)
 }
 
 type service struct {
@@ -51,6 +56,9 @@ func (s *service) Save(ctx context.Context, e domain.Employee) (domain.Employee,
 	if exists {
 		return domain.Employee{}, errors.New("El usuario ya existe")
 	}
+	if !s.repository.ExistsWarehouse(ctx, e.WarehouseID) {
+		return domain.Employee{}, ErrWarehouseNotFound
+	}
 	id, err := s.repository.Save(ctx, e)
 	if err != nil {
 		return domain.Employee{}, err
@@ -66,6 +74,9 @@ func (s *service) Update(ctx context.Context, id int, e domain.Employee) (domain
 		return domain.Employee{}, fmt.Errorf("No existe el empleado con el id %d", id)
 	}
 	updateField := updateField(resul, e)
+	if updateField.WarehouseID != resul.WarehouseID && !s.repository.ExistsWarehouse(ctx, updateField.WarehouseID) {
+		return domain.Employee{}, ErrWarehouseNotFound
+	}
 	err := s.repository.Update(ctx, updateField)
 	if err != nil {
 		return domain.Employee{}, err
@@ -85,18 +96,50 @@ func (s *service) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-func (s *service) GetAllReportInboundOrders(ctx context.Context) ([]domain.ReportInboundOrders, error) {
-	return s.repository.GetAllReportInboundOrders()
+// GetAssignments returns the assignment history of the employee, which outlives
+// the employee once deleted.
+func (s *service) GetAssignments(ctx context.Context, id int) ([]domain.EmployeeAssignment, error) {
+	assignments, err := s.repository.GetAssignments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) == 0 {
+		if resul, _ := s.repository.Get(ctx, id); resul.ID == 0 {
+			return nil, ErrNotFound
+		}
+	}
+	return assignments, nil
+}
+
+func (s *service) GetByWarehouse(ctx context.Context, warehouseId int, at time.Time) ([]domain.Employee, error) {
+	if !s.repository.ExistsWarehouse(ctx, warehouseId) {
+		return nil, ErrWarehouseNotFound
+	}
+	return s.repository.GetByWarehouse(ctx, warehouseId, at)
+}
+
+func (s *service) GetAllReportInboundOrders(ctx context.Context, filter domain.ReportInboundOrdersFilter) ([]domain.ReportInboundOrders, error) {
+	if !validRange(filter) {
+		return nil, ErrInvalidRange
+	}
+	return s.repository.GetAllReportInboundOrders(ctx, filter)
 }
 
-func (s *service) GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int) (domain.ReportInboundOrders, error) {
-	resul, err := s.repository.GetReportByEmployeeIdInboundOrders(employeeId)
+func (s *service) GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int, filter domain.ReportInboundOrdersFilter) (domain.ReportInboundOrders, error) {
+	if !validRange(filter) {
+		return domain.ReportInboundOrders{}, ErrInvalidRange
+	}
+	resul, err := s.repository.GetReportByEmployeeIdInboundOrders(ctx, employeeId, filter)
 	if err != nil {
 		return domain.ReportInboundOrders{}, ErrorEmployeesNoExist
 	}
 	return resul, err
 }
 
+func validRange(filter domain.ReportInboundOrdersFilter) bool {
+	return filter.From.IsZero() || filter.To.IsZero() || filter.From.Before(filter.To)
+}
+
 func updateField(lastE domain.Employee, newE domain.Employee) domain.Employee {
 	if newE.LastName != lastE.LastName && newE.LastName != "" {
 		lastE.LastName = newE.LastName
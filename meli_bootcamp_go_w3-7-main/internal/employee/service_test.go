@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -45,13 +46,28 @@ func (m *dbMock) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
-func (m *dbMock) GetAllReportInboundOrders() ([]domain.ReportInboundOrders, error) {
-	args := m.Called()
+func (m *dbMock) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	args := m.Called(ctx, warehouseId)
+	return args.Bool(0)
+}
+
+func (m *dbMock) GetAssignments(ctx context.Context, employeeId int) ([]domain.EmployeeAssignment, error) {
+	args := m.Called(ctx, employeeId)
+	return args.Get(0).([]domain.EmployeeAssignment), args.Error(1)
+}
+
+func (m *dbMock) GetByWarehouse(ctx context.Context, warehouseId int, at time.Time) ([]domain.Employee, error) {
+	args := m.Called(ctx, warehouseId, at)
+	return args.Get(0).([]domain.Employee), args.Error(1)
+}
+
+func (m *dbMock) GetAllReportInboundOrders(ctx context.Context, filter domain.ReportInboundOrdersFilter) ([]domain.ReportInboundOrders, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]domain.ReportInboundOrders), args.Error(1)
 }
 
-func (m *dbMock) GetReportByEmployeeIdInboundOrders(employeeId int) (domain.ReportInboundOrders, error) {
-	args := m.Called(employeeId)
+func (m *dbMock) GetReportByEmployeeIdInboundOrders(ctx context.Context, employeeId int, filter domain.ReportInboundOrdersFilter) (domain.ReportInboundOrders, error) {
+	args := m.Called(ctx, employeeId, filter)
 	return args.Get(0).(domain.ReportInboundOrders), args.Error(1)
 }
 
@@ -65,6 +81,7 @@ func TestCreateOk(t *testing.T) {
 	repo := new(dbMock)
 	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
 	repo.On("Exists", mock.Anything, mock.Anything).Return(false)
+	repo.On("ExistsWarehouse", mock.Anything, mock.Anything).Return(true)
 	serviceT := NewService(repo)
 	ctx := context.Background()
 	resul, _ := serviceT.Save(ctx, expectedResult)
@@ -146,6 +163,7 @@ func TestUpdateExistent(t *testing.T) {
 	repo := new(dbMock)
 	repo.On("Get", mock.Anything, mock.Anything).Return(expectedResultGet, nil)
 	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	repo.On("ExistsWarehouse", mock.Anything, mock.Anything).Return(true)
 	serviceT := NewService(repo)
 	ctx := context.Background()
 	result, _ := serviceT.Update(ctx, 1, expectedResult)
@@ -203,10 +221,10 @@ func TestGetAllReportInboundOrdersOK(t *testing.T) {
 		InboundOrdersCount: 3,
 	})
 	repo := new(dbMock)
-	repo.On("GetAllReportInboundOrders").Return(expectedResult, nil)
+	repo.On("GetAllReportInboundOrders", mock.Anything, mock.Anything).Return(expectedResult, nil)
 	serviceT := NewService(repo)
 	ctx := context.Background()
-	resul, _ := serviceT.GetAllReportInboundOrders(ctx)
+	resul, _ := serviceT.GetAllReportInboundOrders(ctx, domain.ReportInboundOrdersFilter{})
 	assert.True(t, len(resul) > 0)
 }
 
@@ -220,10 +238,10 @@ func TestGetReportByEmployeeIdInboundOrdersOK(t *testing.T) {
 		InboundOrdersCount: 2,
 	}
 	repo := new(dbMock)
-	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything).Return(expectedResult, nil)
+	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything, mock.Anything, mock.Anything).Return(expectedResult, nil)
 	serviceT := NewService(repo)
 	ctx := context.Background()
-	resul, _ := serviceT.GetReportByEmployeeIdInboundOrders(ctx, 1)
+	resul, _ := serviceT.GetReportByEmployeeIdInboundOrders(ctx, 1, domain.ReportInboundOrdersFilter{})
 	assert.Equal(t, expectedResult, resul)
 }
 
@@ -231,10 +249,80 @@ func TestGetReportByEmployeeIdInboundOrdersErrorQuery(t *testing.T) {
 	expectedResult := domain.ReportInboundOrders{}
 	expectedResultError := errors.New("The employee doesn't exist")
 	repo := new(dbMock)
-	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything).Return(expectedResult, errors.New("Sql Error"))
+	repo.On("GetReportByEmployeeIdInboundOrders", mock.Anything, mock.Anything, mock.Anything).Return(expectedResult, errors.New("Sql Error"))
 	serviceT := NewService(repo)
 	ctx := context.Background()
-	resul, err := serviceT.GetReportByEmployeeIdInboundOrders(ctx, 1)
+	resul, err := serviceT.GetReportByEmployeeIdInboundOrders(ctx, 1, domain.ReportInboundOrdersFilter{})
 	assert.Equal(t, expectedResult, resul)
 	assert.Equal(t, expectedResultError, err)
 }
+
+func TestGetAllReportInboundOrdersInvalidRange(t *testing.T) {
+	from, _ := time.Parse("2006-01-02", "2022-01-07")
+	to, _ := time.Parse("2006-01-02", "2022-01-06")
+	repo := new(dbMock)
+	serviceT := NewService(repo)
+	_, err := serviceT.GetAllReportInboundOrders(context.Background(), domain.ReportInboundOrdersFilter{From: from, To: to})
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	repo.AssertNotCalled(t, "GetAllReportInboundOrders", mock.Anything, mock.Anything)
+}
+
+func TestCreateNoExistWarehouse(t *testing.T) {
+	repo := new(dbMock)
+	repo.On("Exists", mock.Anything, "123456").Return(false)
+	repo.On("ExistsWarehouse", mock.Anything, 9).Return(false)
+	serviceT := NewService(repo)
+	_, err := serviceT.Save(context.Background(), domain.Employee{CardNumberID: "123456", FirstName: "laynerker", LastName: "guerrero", WarehouseID: 9})
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestUpdateNoExistWarehouse(t *testing.T) {
+	current := domain.Employee{ID: 1, CardNumberID: "123456", FirstName: "laynerker", LastName: "guerrero", WarehouseID: 5}
+	repo := new(dbMock)
+	repo.On("Get", mock.Anything, 1).Return(current, nil)
+	repo.On("ExistsWarehouse", mock.Anything, 9).Return(false)
+	serviceT := NewService(repo)
+	_, err := serviceT.Update(context.Background(), 1, domain.Employee{WarehouseID: 9})
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateSameWarehouseSkipsCheck(t *testing.T) {
+	current := domain.Employee{ID: 1, CardNumberID: "123456", FirstName: "laynerker", LastName: "guerrero", WarehouseID: 5}
+	repo := new(dbMock)
+	repo.On("Get", mock.Anything, 1).Return(current, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	serviceT := NewService(repo)
+	_, err := serviceT.Update(context.Background(), 1, domain.Employee{FirstName: "hadassa"})
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "ExistsWarehouse", mock.Anything, mock.Anything)
+}
+
+func TestGetAssignmentsNonExistentEmployee(t *testing.T) {
+	repo := new(dbMock)
+	repo.On("GetAssignments", mock.Anything, 4).Return([]domain.EmployeeAssignment{}, nil)
+	repo.On("Get", mock.Anything, 4).Return(domain.Employee{}, errors.New("sql: no rows in result set"))
+	serviceT := NewService(repo)
+	_, err := serviceT.GetAssignments(context.Background(), 4)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetAssignmentsDeletedEmployeeKeepsHistory(t *testing.T) {
+	repo := new(dbMock)
+	history := []domain.EmployeeAssignment{{ID: 1, EmployeeID: 4, WarehouseID: 5}}
+	repo.On("GetAssignments", mock.Anything, 4).Return(history, nil)
+	serviceT := NewService(repo)
+	assignments, err := serviceT.GetAssignments(context.Background(), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, history, assignments)
+	repo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestGetByWarehouseNonExistent(t *testing.T) {
+	repo := new(dbMock)
+	repo.On("ExistsWarehouse", mock.Anything, 9).Return(false)
+	serviceT := NewService(repo)
+	_, err := serviceT.GetByWarehouse(context.Background(), 9, time.Time{})
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+}
@@ -3,15 +3,31 @@ package inboudOrders
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/stockMovements"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/store"
 )
 
 type Repository interface {
+	GetAll(ctx context.Context, filter domain.InboudOrdersFilter) ([]domain.InboudOrders, int, error)
+	Get(ctx context.Context, id int) (domain.InboudOrders, error)
 	Save(ctx context.Context, i domain.InboudOrders) (int, error)
+	Exists(ctx context.Context, orderNumber string) bool
 	ExistsEmployee(ctx context.Context, employeeId int) bool
+	ExistsWarehouse(ctx context.Context, warehouseId int) bool
+	ExistsProductBatch(ctx context.Context, productBatchId int) bool
+	ExistsEmployeeInWarehouse(ctx context.Context, employeeId, warehouseId int) bool
 }
 
+const (
+	selectInboudOrdersQuery = "SELECT id, order_date, order_number, employee_id, product_batch_id, warehouse_id, quantity FROM inbound_orders"
+	dateTimeLayout          = "2006-01-02 15:04:05"
+)
+
 type repository struct {
 	db *sql.DB
 }
@@ -22,15 +38,106 @@ func NewRepository(db *sql.DB) Repository {
 	}
 }
 
+// GetAll returns the requested page of the orders matching filter, sorted by order_date,
+// together with the total number of matching orders.
+func (r *repository) GetAll(ctx context.Context, filter domain.InboudOrdersFilter) ([]domain.InboudOrders, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	if filter.EmployeeId != 0 {
+		where += " AND employee_id=?"
+		args = append(args, filter.EmployeeId)
+	}
+	if filter.WarehouseID != 0 {
+		where += " AND warehouse_id=?"
+		args = append(args, filter.WarehouseID)
+	}
+	if !filter.From.IsZero() {
+		where += " AND order_date >= ?"
+		args = append(args, filter.From.UTC().Format(dateTimeLayout))
+	}
+	if !filter.To.IsZero() {
+		where += " AND order_date < ?"
+		args = append(args, filter.To.UTC().Format(dateTimeLayout))
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM inbound_orders"+where+";", args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	order := "ASC"
+	if filter.Order == "desc" {
+		order = "DESC"
+	}
+	query := selectInboudOrdersQuery + where + " ORDER BY order_date " + order + ", id " + order + " LIMIT ? OFFSET ?;"
+	rows, err := r.db.Query(query, append(args, filter.Limit, (filter.Page-1)*filter.Limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	orders := []domain.InboudOrders{}
+
+	for rows.Next() {
+		i, err := scanInboudOrder(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		orders = append(orders, i)
+	}
+
+	return orders, total, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.InboudOrders, error) {
+	row := r.db.QueryRow(selectInboudOrdersQuery+" WHERE id=?;", id)
+	return scanInboudOrder(row)
+}
+
+// Save stores the order and adds the received quantity to the batch and to the capacity
+// of its section, recording the inbound movement, all in one transaction. The section of
+// the batch must belong to the warehouse of the order. An order without quantity takes
+// the initial quantity of the batch, which was already received when the batch was
+// created, so it leaves the stock as it is.
 func (r *repository) Save(ctx context.Context, i domain.InboudOrders) (int, error) {
-	query := "INSERT INTO inbound_orders(order_date,order_number,employee_id,product_batch_id,warehouse_id) VALUES (?,?,?,?,?)"
-	stmt, err := r.db.Prepare(query)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var sectionId, warehouseId, currentCapacity, maximumCapacity, initialQuantity int
+	query := "SELECT s.id, s.warehouse_id, s.current_capacity, s.maximum_capacity, pb.initial_quantity FROM product_batches pb INNER JOIN sections s ON s.id = pb.section_id WHERE pb.id=? FOR UPDATE;"
+	err = tx.QueryRowContext(ctx, query, i.ProductBatchId).Scan(&sectionId, &warehouseId, &currentCapacity, &maximumCapacity, &initialQuantity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrProductBatchNotFound
+		}
+		return 0, err
+	}
+	if warehouseId != i.WarehouseID {
+		return 0, fmt.Errorf("%w: la seccion %d pertenece al warehouse %d", ErrProductBatchNotInWarehouse, sectionId, warehouseId)
+	}
+	received := i.Quantity
+	if i.Quantity == 0 {
+		i.Quantity = initialQuantity
+	}
+	if currentCapacity+received > maximumCapacity {
+		return 0, fmt.Errorf("%w: %d + %d > %d", ErrSectionCapacityExceeded, currentCapacity, received, maximumCapacity)
+	}
+
+	query = "INSERT INTO inbound_orders(order_date,order_number,employee_id,product_batch_id,warehouse_id,quantity) VALUES (?,?,?,?,?,?)"
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return 0, err
 	}
+	defer stmt.Close()
 
-	res, err := stmt.Exec(&i.OrderDate, &i.OrderNumber, &i.EmployeeId, &i.ProductBatchId, &i.WarehouseID)
+	res, err := stmt.ExecContext(ctx, &i.OrderDate, &i.OrderNumber, &i.EmployeeId, &i.ProductBatchId, &i.WarehouseID, &i.Quantity)
 	if err != nil {
+		if store.IsDuplicateEntry(err) {
+			return 0, ErrAlreadyExists
+		}
 		return 0, err
 	}
 
@@ -39,12 +146,81 @@ func (r *repository) Save(ctx context.Context, i domain.InboudOrders) (int, erro
 		return 0, err
 	}
 
+	if received > 0 {
+		query = "UPDATE product_batches SET current_quantity=current_quantity+? WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, received, i.ProductBatchId); err != nil {
+			return 0, err
+		}
+		query = "UPDATE sections SET current_capacity=current_capacity+? WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, received, sectionId); err != nil {
+			return 0, err
+		}
+		err = stockMovements.Record(ctx, tx, domain.StockMovement{
+			Type:              stockMovements.TypeInbound,
+			ProductBatchID:    i.ProductBatchId,
+			SectionID:         sectionId,
+			Quantity:          received,
+			EmployeeID:        i.EmployeeId,
+			ReferenceDocument: i.OrderNumber,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return int(id), nil
 }
 
+func (r *repository) Exists(ctx context.Context, orderNumber string) bool {
+	query := "SELECT order_number FROM inbound_orders WHERE order_number=?;"
+	row := r.db.QueryRow(query, orderNumber)
+	err := row.Scan(&orderNumber)
+	return err == nil
+}
+
 func (r *repository) ExistsEmployee(ctx context.Context, employeeId int) bool {
 	query := "SELECT id FROM employees WHERE id=?;"
 	row := r.db.QueryRow(query, employeeId)
 	err := row.Scan(&employeeId)
 	return err == nil
 }
+
+func (r *repository) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	return store.ExistsWarehouse(ctx, r.db, warehouseId)
+}
+
+func (r *repository) ExistsProductBatch(ctx context.Context, productBatchId int) bool {
+	query := "SELECT id FROM product_batches WHERE id=?;"
+	row := r.db.QueryRow(query, productBatchId)
+	err := row.Scan(&productBatchId)
+	return err == nil
+}
+
+func (r *repository) ExistsEmployeeInWarehouse(ctx context.Context, employeeId, warehouseId int) bool {
+	query := "SELECT id FROM employees WHERE id=? AND warehouse_id=?;"
+	row := r.db.QueryRow(query, employeeId, warehouseId)
+	err := row.Scan(&employeeId)
+	return err == nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanInboudOrder(row scanner) (domain.InboudOrders, error) {
+	i := domain.InboudOrders{}
+	var orderDate string
+	err := row.Scan(&i.ID, &orderDate, &i.OrderNumber, &i.EmployeeId, &i.ProductBatchId, &i.WarehouseID, &i.Quantity)
+	if err != nil {
+		return domain.InboudOrders{}, err
+	}
+	i.OrderDate, err = time.Parse(dateTimeLayout, orderDate)
+	if err != nil {
+		return domain.InboudOrders{}, err
+	}
+	return i, nil
+}
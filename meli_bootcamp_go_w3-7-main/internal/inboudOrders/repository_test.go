@@ -6,11 +6,66 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/utils"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
+const selectBatchSectionQuery = "SELECT s.id, s.warehouse_id, s.current_capacity, s.maximum_capacity, pb.initial_quantity FROM product_batches pb INNER JOIN sections s ON s.id = pb.section_id WHERE pb.id=\\? FOR UPDATE;"
+
+func TestRepositorySaveBatchFromOtherWarehouse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectBatchSectionQuery).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "current_capacity", "maximum_capacity", "initial_quantity"}).AddRow(5, 4, 0, 100, 20))
+	mock.ExpectRollback()
+
+	repo := NewRepository(db)
+	_, err = repo.Save(context.Background(), domain.InboudOrders{OrderNumber: "123", EmployeeId: 1, ProductBatchId: 2, WarehouseID: 3, Quantity: 10})
+	assert.ErrorIs(t, err, ErrProductBatchNotInWarehouse)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositorySaveDuplicatedOrderNumber(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectBatchSectionQuery).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "current_capacity", "maximum_capacity", "initial_quantity"}).AddRow(5, 3, 0, 100, 20))
+	mock.ExpectPrepare("INSERT INTO inbound_orders").ExpectExec().
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '123' for key 'inbound_orders_order_number_uindex'"})
+	mock.ExpectRollback()
+
+	repo := NewRepository(db)
+	_, err = repo.Save(context.Background(), domain.InboudOrders{OrderNumber: "123", EmployeeId: 1, ProductBatchId: 2, WarehouseID: 3, Quantity: 10})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepositorySaveWithoutQuantityTakesTheBatchQuantity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectBatchSectionQuery).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "current_capacity", "maximum_capacity", "initial_quantity"}).AddRow(5, 3, 100, 100, 20))
+	mock.ExpectPrepare("INSERT INTO inbound_orders").ExpectExec().
+		WithArgs(sqlmock.AnyArg(), "123", 1, 2, 3, 20).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	repo := NewRepository(db)
+	id, err := repo.Save(context.Background(), domain.InboudOrders{OrderNumber: "123", EmployeeId: 1, ProductBatchId: 2, WarehouseID: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository(t *testing.T) {
 	db, err := utils.InitDB()
 	assert.NoError(t, err)
@@ -2,14 +2,33 @@ package inboudOrders
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 )
 
-var ErrorEmployeesNoExist = errors.New("El empleado no existe")
+var (
+	ErrorEmployeesNoExist         = errors.New("El empleado no existe")
+	ErrWarehouseNotFound          = errors.New("El warehouse no existe")
+	ErrProductBatchNotFound       = errors.New("El product batch no existe")
+	ErrEmployeeNotInWarehouse     = errors.New("El empleado no pertenece al warehouse")
+	ErrProductBatchNotInWarehouse = errors.New("La seccion del product batch no pertenece al warehouse")
+	ErrAlreadyExists              = errors.New("Ya existe una inbound order con ese order_number")
+	ErrSectionCapacityExceeded    = errors.New("La seccion del product batch no tiene capacidad suficiente")
+	ErrInvalidQuantity            = errors.New("La quantity no puede ser negativa")
+	ErrNotFound                   = errors.New("La inbound order no existe")
+	ErrInvalidFilter              = errors.New("page y limit deben ser positivos, limit como maximo 100 y order asc o desc")
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
 
 type Service interface {
+	GetAll(ctx context.Context, filter domain.InboudOrdersFilter) (domain.InboudOrdersPage, error)
+	Get(ctx context.Context, id int) (domain.InboudOrders, error)
 	Save(ctx context.Context, i domain.InboudOrders) (domain.InboudOrders, error)
 }
 
@@ -23,15 +42,67 @@ func NewService(repository Repository) Service {
 	}
 }
 
+func (s *service) GetAll(ctx context.Context, filter domain.InboudOrdersFilter) (domain.InboudOrdersPage, error) {
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+	if filter.Page < 0 || filter.Limit < 0 || filter.Limit > maxLimit || (filter.Order != "" && filter.Order != "asc" && filter.Order != "desc") {
+		return domain.InboudOrdersPage{}, ErrInvalidFilter
+	}
+	orders, total, err := s.repository.GetAll(ctx, filter)
+	if err != nil {
+		return domain.InboudOrdersPage{}, err
+	}
+	return domain.InboudOrdersPage{
+		Data:  orders,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}, nil
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.InboudOrders, error) {
+	i, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.InboudOrders{}, ErrNotFound
+		}
+		return domain.InboudOrders{}, err
+	}
+	return i, nil
+}
+
 func (s *service) Save(ctx context.Context, i domain.InboudOrders) (domain.InboudOrders, error) {
+	if i.Quantity < 0 {
+		return domain.InboudOrders{}, ErrInvalidQuantity
+	}
 	exists := s.repository.ExistsEmployee(ctx, i.EmployeeId)
 	if !exists {
 		return domain.InboudOrders{}, ErrorEmployeesNoExist
 	}
+	if !s.repository.ExistsWarehouse(ctx, i.WarehouseID) {
+		return domain.InboudOrders{}, ErrWarehouseNotFound
+	}
+	if !s.repository.ExistsProductBatch(ctx, i.ProductBatchId) {
+		return domain.InboudOrders{}, ErrProductBatchNotFound
+	}
+	if !s.repository.ExistsEmployeeInWarehouse(ctx, i.EmployeeId, i.WarehouseID) {
+		return domain.InboudOrders{}, ErrEmployeeNotInWarehouse
+	}
+	if s.repository.Exists(ctx, i.OrderNumber) {
+		return domain.InboudOrders{}, ErrAlreadyExists
+	}
 	id, err := s.repository.Save(ctx, i)
 	if err != nil {
 		return domain.InboudOrders{}, err
 	}
+	if i.Quantity == 0 {
+		// The quantity was taken from the batch.
+		return s.repository.Get(ctx, id)
+	}
 	i.ID = id
 	return i, nil
 }
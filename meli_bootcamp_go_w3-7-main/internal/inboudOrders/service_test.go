@@ -2,6 +2,7 @@ package inboudOrders
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -24,6 +25,36 @@ func (m *dbIOMock) ExistsEmployee(ctx context.Context, employeeId int) bool {
 	return args.Bool(0)
 }
 
+func (m *dbIOMock) GetAll(ctx context.Context, filter domain.InboudOrdersFilter) ([]domain.InboudOrders, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.InboudOrders), args.Int(1), args.Error(2)
+}
+
+func (m *dbIOMock) Get(ctx context.Context, id int) (domain.InboudOrders, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.InboudOrders), args.Error(1)
+}
+
+func (m *dbIOMock) Exists(ctx context.Context, orderNumber string) bool {
+	args := m.Called(ctx, orderNumber)
+	return args.Bool(0)
+}
+
+func (m *dbIOMock) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	args := m.Called(ctx, warehouseId)
+	return args.Bool(0)
+}
+
+func (m *dbIOMock) ExistsProductBatch(ctx context.Context, productBatchId int) bool {
+	args := m.Called(ctx, productBatchId)
+	return args.Bool(0)
+}
+
+func (m *dbIOMock) ExistsEmployeeInWarehouse(ctx context.Context, employeeId, warehouseId int) bool {
+	args := m.Called(ctx, employeeId, warehouseId)
+	return args.Bool(0)
+}
+
 func TestCreateOkInboudOrders(t *testing.T) {
 	type response struct {
 		Data domain.InboudOrders
@@ -36,10 +67,15 @@ func TestCreateOkInboudOrders(t *testing.T) {
 		EmployeeId:     1,
 		ProductBatchId: 2,
 		WarehouseID:    3,
+		Quantity:       10,
 	}
 	repo := new(dbIOMock)
 	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
 	repo.On("ExistsEmployee", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 2).Return(true)
+	repo.On("ExistsEmployeeInWarehouse", mock.Anything, 1, 3).Return(true)
+	repo.On("Exists", mock.Anything, "12312").Return(false)
 	serviceIO := NewService(repo)
 	ctx := context.Background()
 	resul, _ := serviceIO.Save(ctx, expectedResult)
@@ -67,3 +103,118 @@ func TestCreateInboudOrdersNoExistEmployee(t *testing.T) {
 	_, err := serviceIO.Save(ctx, expectedResult)
 	assert.Error(t, err)
 }
+
+func newInboudOrder() domain.InboudOrders {
+	orderDate, _ := time.Parse("2006-01-02", "2022-01-06")
+	return domain.InboudOrders{
+		OrderDate:      orderDate,
+		OrderNumber:    "12312",
+		EmployeeId:     1,
+		ProductBatchId: 2,
+		WarehouseID:    3,
+		Quantity:       10,
+	}
+}
+
+func TestCreateInboudOrdersInvalidQuantity(t *testing.T) {
+	repo := new(dbIOMock)
+	serviceIO := NewService(repo)
+	order := newInboudOrder()
+	order.Quantity = -5
+	_, err := serviceIO.Save(context.Background(), order)
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCreateInboudOrdersWithoutQuantity(t *testing.T) {
+	repo := new(dbIOMock)
+	order := newInboudOrder()
+	order.Quantity = 0
+	saved := order
+	saved.ID = 1
+	saved.Quantity = 20
+	repo.On("ExistsEmployee", mock.Anything, 1).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 2).Return(true)
+	repo.On("ExistsEmployeeInWarehouse", mock.Anything, 1, 3).Return(true)
+	repo.On("Exists", mock.Anything, "12312").Return(false)
+	repo.On("Save", mock.Anything, order).Return(1, nil)
+	repo.On("Get", mock.Anything, 1).Return(saved, nil)
+	serviceIO := NewService(repo)
+	result, err := serviceIO.Save(context.Background(), order)
+	assert.Nil(t, err)
+	assert.Equal(t, saved, result)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateInboudOrdersNoExistWarehouse(t *testing.T) {
+	repo := new(dbIOMock)
+	repo.On("ExistsEmployee", mock.Anything, 1).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(false)
+	serviceIO := NewService(repo)
+	_, err := serviceIO.Save(context.Background(), newInboudOrder())
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+}
+
+func TestCreateInboudOrdersNoExistProductBatch(t *testing.T) {
+	repo := new(dbIOMock)
+	repo.On("ExistsEmployee", mock.Anything, 1).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 2).Return(false)
+	serviceIO := NewService(repo)
+	_, err := serviceIO.Save(context.Background(), newInboudOrder())
+	assert.ErrorIs(t, err, ErrProductBatchNotFound)
+}
+
+func TestCreateInboudOrdersEmployeeOtherWarehouse(t *testing.T) {
+	repo := new(dbIOMock)
+	repo.On("ExistsEmployee", mock.Anything, 1).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 2).Return(true)
+	repo.On("ExistsEmployeeInWarehouse", mock.Anything, 1, 3).Return(false)
+	serviceIO := NewService(repo)
+	_, err := serviceIO.Save(context.Background(), newInboudOrder())
+	assert.ErrorIs(t, err, ErrEmployeeNotInWarehouse)
+}
+
+func TestCreateInboudOrdersDuplicated(t *testing.T) {
+	repo := new(dbIOMock)
+	repo.On("ExistsEmployee", mock.Anything, 1).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 2).Return(true)
+	repo.On("ExistsEmployeeInWarehouse", mock.Anything, 1, 3).Return(true)
+	repo.On("Exists", mock.Anything, "12312").Return(true)
+	serviceIO := NewService(repo)
+	_, err := serviceIO.Save(context.Background(), newInboudOrder())
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestGetAllInboudOrdersDefaults(t *testing.T) {
+	repo := new(dbIOMock)
+	repo.On("GetAll", mock.Anything, domain.InboudOrdersFilter{EmployeeId: 1, Page: 1, Limit: 20}).Return([]domain.InboudOrders{newInboudOrder()}, 21, nil)
+	serviceIO := NewService(repo)
+	page, err := serviceIO.GetAll(context.Background(), domain.InboudOrdersFilter{EmployeeId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, 20, page.Limit)
+	assert.Equal(t, 21, page.Total)
+	assert.Len(t, page.Data, 1)
+}
+
+func TestGetAllInboudOrdersInvalidFilter(t *testing.T) {
+	repo := new(dbIOMock)
+	serviceIO := NewService(repo)
+	_, err := serviceIO.GetAll(context.Background(), domain.InboudOrdersFilter{Limit: 500})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+	_, err = serviceIO.GetAll(context.Background(), domain.InboudOrdersFilter{Order: "sideways"})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestGetInboudOrdersNonExistent(t *testing.T) {
+	repo := new(dbIOMock)
+	repo.On("Get", mock.Anything, 9).Return(domain.InboudOrders{}, sql.ErrNoRows)
+	serviceIO := NewService(repo)
+	_, err := serviceIO.Get(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
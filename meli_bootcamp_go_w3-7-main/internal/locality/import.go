@@ -0,0 +1,312 @@
+package locality
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Import formats
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Import row statuses
+const (
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusInvalid = "invalid"
+)
+
+// importBatchSize is the number of localities saved per transaction.
+const importBatchSize = 100
+
+// Longest values the schema stores: localities.zip_code is varchar(10),
+// localities.locality_name is TEXT (bytes), and provinces.province_name and
+// countries.country_name are varchar(100).
+const (
+	maxZipCodeLength      = 10
+	maxLocalityNameBytes  = 65535
+	maxProvinceNameLength = 100
+	maxCountryNameLength  = 100
+)
+
+// importColumns are the CSV header names an import file must contain, in any order.
+// NDJSON lines use the same names as keys.
+var importColumns = []string{"zip_code", "locality", "province", "country"}
+
+// importLine is a locality as written in an import file, with its province and
+// country by name.
+type importLine struct {
+	ZipCode  string `json:"zip_code"`
+	Locality string `json:"locality"`
+	Province string `json:"province"`
+	Country  string `json:"country"`
+}
+
+// importRecord is a line read from an import file, or the reason it could not
+// be read.
+type importRecord struct {
+	row  int
+	line importLine
+	err  error
+}
+
+// provinceKey identifies a province and country pair by name. Each pair is
+// resolved against the provinces and countries tables once per import.
+type provinceKey struct {
+	province string
+	country  string
+}
+
+// resolvedProvince is the outcome of resolving a provinceKey.
+type resolvedProvince struct {
+	province domain.Province
+	err      error
+}
+
+// Import reads localities from r and creates the ones whose zip code is not
+// stored yet. The province and country names of every row must match an
+// existing province of that country, otherwise the row is invalid. Rows are
+// saved in batches of importBatchSize per transaction and reported one by one.
+// Batches committed before a database error are kept.
+func (l *service) Import(ctx context.Context, format string, r io.Reader) (domain.LocalityImportReport, error) {
+	var records []importRecord
+	var err error
+	switch format {
+	case FormatCSV:
+		records, err = parseCSV(r)
+	case FormatNDJSON:
+		records, err = parseNDJSON(r)
+	default:
+		err = fmt.Errorf("%w: unsupported format %q", ErrInvalidFormat, format)
+	}
+	if err != nil {
+		return domain.LocalityImportReport{}, err
+	}
+
+	report := domain.LocalityImportReport{Rows: make([]domain.LocalityImportRow, 0, len(records))}
+	resolved := map[provinceKey]resolvedProvince{}
+	seen := map[string]bool{}
+	var batch []domain.Locality
+	var batchRows []int
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ids, err := l.repo.SaveBatch(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for i, idx := range batchRows {
+			if ids[i] == 0 {
+				report.Rows[idx].Status = ImportStatusSkipped
+				report.Rows[idx].Error = "zip code already exists"
+				continue
+			}
+			report.Rows[idx].Status = ImportStatusCreated
+			report.Rows[idx].ID = ids[i]
+		}
+		batch, batchRows = nil, nil
+		return nil
+	}
+
+	for _, rec := range records {
+		row := domain.LocalityImportRow{Row: rec.row, ZipCode: rec.line.ZipCode}
+		err := rec.err
+		if err == nil {
+			err = validImportLine(rec.line)
+		}
+		if err == nil && seen[rec.line.ZipCode] {
+			row.Status = ImportStatusSkipped
+			row.Error = "zip code repeated in the file"
+			report.Rows = append(report.Rows, row)
+			continue
+		}
+		var province domain.Province
+		if err == nil {
+			key := provinceKey{strings.ToLower(rec.line.Province), strings.ToLower(rec.line.Country)}
+			res, ok := resolved[key]
+			if !ok {
+				res.province, res.err = l.repo.GetProvinceByName(ctx, rec.line.Country, rec.line.Province)
+				if errors.Is(res.err, sql.ErrNoRows) {
+					res.err = fmt.Errorf("%w: %q in country %q", ErrProvinceNotFound, rec.line.Province, rec.line.Country)
+				} else if res.err != nil {
+					return domain.LocalityImportReport{}, res.err
+				}
+				resolved[key] = res
+			}
+			province, err = res.province, res.err
+		}
+		if err != nil {
+			row.Status = ImportStatusInvalid
+			row.Error = err.Error()
+			report.Rows = append(report.Rows, row)
+			continue
+		}
+
+		seen[rec.line.ZipCode] = true
+		report.Rows = append(report.Rows, row)
+		batch = append(batch, domain.Locality{
+			ZipCode:      rec.line.ZipCode,
+			LocalityName: rec.line.Locality,
+			ProvinceID:   province.ID,
+			CountryID:    province.CountryID,
+		})
+		batchRows = append(batchRows, len(report.Rows)-1)
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return domain.LocalityImportReport{}, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return domain.LocalityImportReport{}, err
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case ImportStatusCreated:
+			report.Created++
+		case ImportStatusSkipped:
+			report.Skipped++
+		case ImportStatusInvalid:
+			report.Invalid++
+		}
+	}
+	return report, nil
+}
+
+// validImportLine rejects the lines the localities, provinces and countries
+// columns cannot store, so they are reported as invalid instead of failing
+// their whole batch.
+func validImportLine(line importLine) error {
+	switch {
+	case line.ZipCode == "":
+		return errors.New("zip_code is required")
+	case line.Locality == "":
+		return errors.New("locality is required")
+	case line.Province == "":
+		return errors.New("province is required")
+	case line.Country == "":
+		return errors.New("country is required")
+	}
+	for _, field := range []struct {
+		name  string
+		value string
+	}{{"zip_code", line.ZipCode}, {"locality", line.Locality}, {"province", line.Province}, {"country", line.Country}} {
+		if !utf8.ValidString(field.value) {
+			return fmt.Errorf("%s is not valid UTF-8", field.name)
+		}
+	}
+	switch {
+	case utf8.RuneCountInString(line.ZipCode) > maxZipCodeLength:
+		return fmt.Errorf("zip_code must have at most %d characters", maxZipCodeLength)
+	case len(line.Locality) > maxLocalityNameBytes:
+		return fmt.Errorf("locality must have at most %d bytes", maxLocalityNameBytes)
+	case utf8.RuneCountInString(line.Province) > maxProvinceNameLength:
+		return fmt.Errorf("province must have at most %d characters", maxProvinceNameLength)
+	case utf8.RuneCountInString(line.Country) > maxCountryNameLength:
+		return fmt.Errorf("country must have at most %d characters", maxCountryNameLength)
+	}
+	return nil
+}
+
+// parseCSV reads a CSV file with a header row naming the importColumns.
+func parseCSV(r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: missing header", ErrInvalidFormat)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %s", ErrInvalidFormat, name)
+		}
+	}
+
+	var records []importRecord
+	for row := 1; ; row++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+			}
+			records = append(records, importRecord{row: row, err: parseErr.Err})
+			continue
+		}
+		field := func(name string) string {
+			if i := columns[name]; i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		records = append(records, importRecord{row: row, line: trimImportLine(importLine{
+			ZipCode:  field("zip_code"),
+			Locality: field("locality"),
+			Province: field("province"),
+			Country:  field("country"),
+		})})
+	}
+	return records, nil
+}
+
+func trimImportLine(line importLine) importLine {
+	return importLine{
+		ZipCode:  strings.TrimSpace(line.ZipCode),
+		Locality: strings.TrimSpace(line.Locality),
+		Province: strings.TrimSpace(line.Province),
+		Country:  strings.TrimSpace(line.Country),
+	}
+}
+
+// parseNDJSON reads one JSON locality per line. Blank lines are ignored and
+// do not count as rows.
+func parseNDJSON(r io.Reader) ([]importRecord, error) {
+	scanner := bufio.NewScanner(r)
+	var records []importRecord
+	row := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		row++
+		rec := importRecord{row: row}
+		if err := json.Unmarshal([]byte(line), &rec.line); err != nil {
+			rec.err = fmt.Errorf("invalid json: %s", err)
+		}
+		rec.line = trimImportLine(rec.line)
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFormat, err)
+	}
+	return records, nil
+}
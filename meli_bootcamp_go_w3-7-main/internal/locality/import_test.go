@@ -0,0 +1,139 @@
+package locality
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var buenosAires = domain.Province{ID: 2, ProvinceName: "Buenos Aires", CountryID: 1}
+
+func TestImportCSV(t *testing.T) {
+	file := "zip_code,locality,province,country\n" +
+		"6700,Lujan,Buenos Aires,Argentina\n" +
+		"1000,Capital,buenos aires,argentina\n" +
+		"6700,Lujan,Buenos Aires,Argentina\n" +
+		",Sin zip,Buenos Aires,Argentina\n" +
+		"5000,Cordoba,,Argentina\n" +
+		"8000,Bahia,Buenos Aires,Chile\n"
+	repo := new(repoM)
+	repo.On("GetProvinceByName", mock.Anything, "Argentina", "Buenos Aires").Return(buenosAires, nil)
+	repo.On("GetProvinceByName", mock.Anything, "Chile", "Buenos Aires").Return(domain.Province{}, sql.ErrNoRows)
+	repo.On("SaveBatch", mock.Anything, []domain.Locality{
+		{ZipCode: "6700", LocalityName: "Lujan", ProvinceID: 2, CountryID: 1},
+		{ZipCode: "1000", LocalityName: "Capital", ProvinceID: 2, CountryID: 1},
+	}).Return([]int{10, 0}, nil)
+	s := NewService(repo, new(carryServiceM))
+
+	report, err := s.Import(context.Background(), FormatCSV, strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 3, report.Invalid)
+	assert.Equal(t, domain.LocalityImportRow{Row: 1, ZipCode: "6700", Status: ImportStatusCreated, ID: 10}, report.Rows[0])
+	assert.Equal(t, ImportStatusSkipped, report.Rows[1].Status)
+	assert.Equal(t, ImportStatusSkipped, report.Rows[2].Status)
+	assert.Equal(t, ImportStatusInvalid, report.Rows[3].Status)
+	assert.Equal(t, "province is required", report.Rows[4].Error)
+	assert.Equal(t, 6, report.Rows[5].Row)
+	assert.Equal(t, ImportStatusInvalid, report.Rows[5].Status)
+	assert.Contains(t, report.Rows[5].Error, ErrProvinceNotFound.Error())
+	repo.AssertNumberOfCalls(t, "GetProvinceByName", 2)
+}
+
+func TestImportRejectsValuesTheSchemaCannotStore(t *testing.T) {
+	file := "zip_code,locality,province,country\n" +
+		"6700,Lujan,Buenos Aires,Argentina\n" +
+		"12345678901,Largo,Buenos Aires,Argentina\n" +
+		"1000,Capital," + strings.Repeat("p", 101) + ",Argentina\n" +
+		"2000,Rosario\xff,Buenos Aires,Argentina\n"
+	repo := new(repoM)
+	repo.On("GetProvinceByName", mock.Anything, "Argentina", "Buenos Aires").Return(buenosAires, nil)
+	repo.On("SaveBatch", mock.Anything, []domain.Locality{
+		{ZipCode: "6700", LocalityName: "Lujan", ProvinceID: 2, CountryID: 1},
+	}).Return([]int{10}, nil)
+	s := NewService(repo, new(carryServiceM))
+
+	report, err := s.Import(context.Background(), FormatCSV, strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 3, report.Invalid)
+	assert.Equal(t, "zip_code must have at most 10 characters", report.Rows[1].Error)
+	assert.Equal(t, "province must have at most 100 characters", report.Rows[2].Error)
+	assert.Equal(t, "locality is not valid UTF-8", report.Rows[3].Error)
+	repo.AssertNumberOfCalls(t, "GetProvinceByName", 1)
+}
+
+func TestImportCSVMissingColumn(t *testing.T) {
+	s := NewService(new(repoM), new(carryServiceM))
+	_, err := s.Import(context.Background(), FormatCSV, strings.NewReader("zip_code,locality,province\n6700,Lujan,Buenos Aires\n"))
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestImportNDJSON(t *testing.T) {
+	file := `{"zip_code":"6700","locality":"Lujan","province":"Buenos Aires","country":"Argentina"}
+
+{"zip_code":"1000",`
+	repo := new(repoM)
+	repo.On("GetProvinceByName", mock.Anything, "Argentina", "Buenos Aires").Return(buenosAires, nil)
+	repo.On("SaveBatch", mock.Anything, []domain.Locality{{ZipCode: "6700", LocalityName: "Lujan", ProvinceID: 2, CountryID: 1}}).Return([]int{10}, nil)
+	s := NewService(repo, new(carryServiceM))
+
+	report, err := s.Import(context.Background(), FormatNDJSON, strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Invalid)
+	assert.Equal(t, 2, report.Rows[1].Row)
+}
+
+func TestImportBatches(t *testing.T) {
+	var file strings.Builder
+	for i := 0; i < importBatchSize+1; i++ {
+		file.WriteString(`{"zip_code":"` + fmt.Sprintf("%05d", i) + `","locality":"L","province":"Buenos Aires","country":"Argentina"}` + "\n")
+	}
+	repo := new(repoM)
+	repo.On("GetProvinceByName", mock.Anything, "Argentina", "Buenos Aires").Return(buenosAires, nil)
+	repo.On("SaveBatch", mock.Anything, mock.MatchedBy(func(ls []domain.Locality) bool { return len(ls) == importBatchSize })).Return(make([]int, importBatchSize), nil).Once()
+	repo.On("SaveBatch", mock.Anything, mock.MatchedBy(func(ls []domain.Locality) bool { return len(ls) == 1 })).Return([]int{7}, nil).Once()
+	s := NewService(repo, new(carryServiceM))
+
+	report, err := s.Import(context.Background(), FormatNDJSON, strings.NewReader(file.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, importBatchSize, report.Skipped)
+	repo.AssertNumberOfCalls(t, "SaveBatch", 2)
+	repo.AssertNumberOfCalls(t, "GetProvinceByName", 1)
+}
+
+func TestImportBatchError(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProvinceByName", mock.Anything, "Argentina", "Buenos Aires").Return(buenosAires, nil)
+	repo.On("SaveBatch", mock.Anything, mock.Anything).Return([]int(nil), errors.New("connection lost"))
+	s := NewService(repo, new(carryServiceM))
+
+	_, err := s.Import(context.Background(), FormatCSV, strings.NewReader("zip_code,locality,province,country\n6700,Lujan,Buenos Aires,Argentina\n"))
+	assert.EqualError(t, err, "connection lost")
+}
+
+func TestImportProvinceLookupError(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProvinceByName", mock.Anything, "Argentina", "Buenos Aires").Return(domain.Province{}, errors.New("connection lost"))
+	s := NewService(repo, new(carryServiceM))
+
+	_, err := s.Import(context.Background(), FormatCSV, strings.NewReader("zip_code,locality,province,country\n6700,Lujan,Buenos Aires,Argentina\n"))
+	assert.EqualError(t, err, "connection lost")
+	repo.AssertNotCalled(t, "SaveBatch", mock.Anything, mock.Anything)
+}
+
+func TestImportUnsupportedFormat(t *testing.T) {
+	s := NewService(new(repoM), new(carryServiceM))
+	_, err := s.Import(context.Background(), "xml", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
@@ -3,17 +3,30 @@ package locality
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/store"
 )
 
 type Repository interface {
 	Save(ctx context.Context, l domain.Locality) (int, error)
+	SaveBatch(ctx context.Context, ls []domain.Locality) ([]int, error)
 	GetAll(ctx context.Context) ([]domain.Locality, error)
+	Get(ctx context.Context, id int) (domain.Locality, error)
 	GetByZipCode(ctx context.Context, zipCode string) (domain.Locality, error)
 	GetSellers(ctx context.Context, l domain.Locality) ([]domain.Seller, error)
 	Exists(ctx context.Context, id string) bool
+	ExistsCountry(ctx context.Context, countryId int) bool
+	GetProvinceCountry(ctx context.Context, provinceId int) (int, error)
+	GetProvinceByName(ctx context.Context, countryName, provinceName string) (domain.Province, error)
+	IsReferenced(ctx context.Context, id int) bool
+	Update(ctx context.Context, l domain.Locality) error
+	Delete(ctx context.Context, id int) error
 }
+
+const selectLocalitiesQuery = "SELECT l.id, l.zip_code, l.locality_name, l.province_id, p.province_name, p.country_id, c.country_name FROM localities l INNER JOIN provinces p ON p.id = l.province_id INNER JOIN countries c ON c.id = p.country_id"
+
 type repository struct {
 	db *sql.DB
 }
@@ -24,36 +37,46 @@ func NewRepository(db *sql.DB) Repository {
 	}
 }
 func (r *repository) GetAll(ctx context.Context) ([]domain.Locality, error) {
-	query := "SELECT * FROM localities"
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(selectLocalitiesQuery + ";")
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 	var localities []domain.Locality
 	for rows.Next() {
 		s := domain.Locality{}
-		_ = rows.Scan(&s.ID, s.ZipCode, &s.LocalityName, &s.ProvinceName, &s.CountryName)
+		_ = rows.Scan(&s.ID, &s.ZipCode, &s.LocalityName, &s.ProvinceID, &s.ProvinceName, &s.CountryID, &s.CountryName)
 		localities = append(localities, s)
 	}
 	return localities, nil
 }
+func (r *repository) Get(ctx context.Context, id int) (domain.Locality, error) {
+	query := selectLocalitiesQuery + " WHERE l.id=?;"
+	row := r.db.QueryRow(query, id)
+	s := domain.Locality{}
+	err := row.Scan(&s.ID, &s.ZipCode, &s.LocalityName, &s.ProvinceID, &s.ProvinceName, &s.CountryID, &s.CountryName)
+	if err != nil {
+		return domain.Locality{}, err
+	}
+	return s, nil
+}
 func (r *repository) GetByZipCode(ctx context.Context, zipCode string) (domain.Locality, error) {
-	query := "SELECT * FROM localities WHERE zip_code=?"
+	query := selectLocalitiesQuery + " WHERE l.zip_code=?;"
 	row := r.db.QueryRow(query, zipCode)
 	s := domain.Locality{}
-	err := row.Scan(&s.ID, &s.ZipCode, &s.LocalityName, &s.ProvinceName, &s.CountryName)
+	err := row.Scan(&s.ID, &s.ZipCode, &s.LocalityName, &s.ProvinceID, &s.ProvinceName, &s.CountryID, &s.CountryName)
 	if err != nil {
 		return domain.Locality{}, err
 	}
 	return s, nil
 }
 func (r *repository) Save(ctx context.Context, l domain.Locality) (int, error) {
-	query := "INSERT INTO localities (zip_code, locality_name, province_name, country_name) VALUES (?,?, ?, ?)"
+	query := "INSERT INTO localities (zip_code, locality_name, province_id) VALUES (?, ?, ?)"
 	stmt, err := r.db.Prepare(query)
 	if err != nil {
 		return 0, err
 	}
-	res, err := stmt.Exec(l.ZipCode, l.LocalityName, l.ProvinceName, l.CountryName)
+	res, err := stmt.Exec(l.ZipCode, l.LocalityName, l.ProvinceID)
 	if err != nil {
 		return 0, err
 	}
@@ -63,12 +86,130 @@ func (r *repository) Save(ctx context.Context, l domain.Locality) (int, error) {
 	}
 	return int(id), nil
 }
+
+// SaveBatch inserts the localities in a single transaction and returns their
+// ids in order. A locality whose zip code is already stored is left untouched
+// and gets id 0.
+func (r *repository) SaveBatch(ctx context.Context, ls []domain.Locality) ([]int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	exists, err := tx.PrepareContext(ctx, "SELECT id FROM localities WHERE zip_code=?;")
+	if err != nil {
+		return nil, err
+	}
+	defer exists.Close()
+	insert, err := tx.PrepareContext(ctx, "INSERT INTO localities (zip_code, locality_name, province_id) VALUES (?, ?, ?);")
+	if err != nil {
+		return nil, err
+	}
+	defer insert.Close()
+
+	ids := make([]int, len(ls))
+	for i, l := range ls {
+		var id int
+		err := exists.QueryRowContext(ctx, l.ZipCode).Scan(&id)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		res, err := insert.ExecContext(ctx, l.ZipCode, l.LocalityName, l.ProvinceID)
+		if err != nil {
+			if store.IsDuplicateEntry(err) {
+				continue
+			}
+			return nil, err
+		}
+		lastId, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = int(lastId)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+func (r *repository) Update(ctx context.Context, l domain.Locality) error {
+	query := "UPDATE localities SET zip_code=?, locality_name=?, province_id=? WHERE id=?"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(l.ZipCode, l.LocalityName, l.ProvinceID, l.ID)
+	return err
+}
+func (r *repository) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM localities WHERE id=?"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	res, err := stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect < 1 {
+		return ErrNotFound
+	}
+	return nil
+}
 func (r *repository) Exists(ctx context.Context, id string) bool {
 	query := "SELECT zip_code FROM localities WHERE zip_code=?;"
 	row := r.db.QueryRow(query, id)
 	err := row.Scan(&id)
 	return err == nil
 }
+func (r *repository) ExistsCountry(ctx context.Context, countryId int) bool {
+	query := "SELECT id FROM countries WHERE id=?;"
+	row := r.db.QueryRow(query, countryId)
+	err := row.Scan(&countryId)
+	return err == nil
+}
+
+// GetProvinceCountry returns the id of the country the province belongs to.
+func (r *repository) GetProvinceCountry(ctx context.Context, provinceId int) (int, error) {
+	query := "SELECT country_id FROM provinces WHERE id=?;"
+	var countryId int
+	err := r.db.QueryRow(query, provinceId).Scan(&countryId)
+	if err != nil {
+		return 0, err
+	}
+	return countryId, nil
+}
+
+// GetProvinceByName returns the province with the given name in the country with the
+// given name. Names are compared with the column collation.
+func (r *repository) GetProvinceByName(ctx context.Context, countryName, provinceName string) (domain.Province, error) {
+	query := "SELECT p.id, p.province_name, p.country_id FROM provinces p INNER JOIN countries c ON c.id = p.country_id WHERE c.country_name=? AND p.province_name=?;"
+	p := domain.Province{}
+	err := r.db.QueryRowContext(ctx, query, countryName, provinceName).Scan(&p.ID, &p.ProvinceName, &p.CountryID)
+	if err != nil {
+		return domain.Province{}, err
+	}
+	return p, nil
+}
+
+// IsReferenced reports whether any seller or carry is located in the locality.
+func (r *repository) IsReferenced(ctx context.Context, id int) bool {
+	query := "SELECT id FROM sellers WHERE localities_id=? UNION ALL SELECT id FROM carries WHERE locality_id=? LIMIT 1;"
+	row := r.db.QueryRow(query, id, id)
+	err := row.Scan(&id)
+	return err == nil
+}
 func (r *repository) GetSellers(ctx context.Context, l domain.Locality) ([]domain.Seller, error) {
 	query := "SELECT * FROM sellers WHERE localities_id=?"
 	stmt, err := r.db.PrepareContext(ctx, query)
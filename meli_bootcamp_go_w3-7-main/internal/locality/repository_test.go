@@ -15,8 +15,8 @@ func TestCreateOk(t *testing.T) {
 	localityToSave := domain.Locality{
 		ZipCode:      "6700",
 		LocalityName: "Lujan",
-		ProvinceName: "Buenos Aires",
-		CountryName:  "Argentina",
+		ProvinceID:   1,
+		CountryID:    1,
 	}
 	db, mock, sqlMockErr := sqlmock.New()
 	assert.Nil(t, sqlMockErr)
@@ -24,7 +24,7 @@ func TestCreateOk(t *testing.T) {
 	mock.
 		ExpectPrepare("INSERT INTO localities").
 		ExpectExec().
-		WithArgs(localityToSave.ZipCode, localityToSave.LocalityName, localityToSave.ProvinceName, localityToSave.CountryName).
+		WithArgs(localityToSave.ZipCode, localityToSave.LocalityName, localityToSave.ProvinceID).
 		WillReturnResult(sqlmock.NewResult(1, 1)).
 		WillReturnError(nil)
 	localityRepository := NewRepository(db)
@@ -37,8 +37,8 @@ func TestCreateConflict(t *testing.T) {
 	localityToSave := domain.Locality{
 		ZipCode:      "6700",
 		LocalityName: "Lujan",
-		ProvinceName: "Buenos Aires",
-		CountryName:  "Argentina",
+		ProvinceID:   1,
+		CountryID:    1,
 	}
 	db, mock, sqlMockErr := sqlmock.New()
 	assert.Nil(t, sqlMockErr)
@@ -46,7 +46,7 @@ func TestCreateConflict(t *testing.T) {
 	mock.
 		ExpectPrepare("INSERT INTO localities").
 		ExpectExec().
-		WithArgs(localityToSave.ZipCode, localityToSave.LocalityName, localityToSave.ProvinceName, localityToSave.CountryName).
+		WithArgs(localityToSave.ZipCode, localityToSave.LocalityName, localityToSave.ProvinceID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	localityRepository := NewRepository(db)
 	ctx := context.Background()
@@ -56,13 +56,13 @@ func TestCreateConflict(t *testing.T) {
 	localityCompare := domain.Locality{
 		ZipCode:      "6700",
 		LocalityName: "Lujan",
-		ProvinceName: "Buenos Aires",
-		CountryName:  "Argentina",
+		ProvinceID:   1,
+		CountryID:    1,
 	}
 	mock.
 		ExpectPrepare("INSERT INTO localities").
 		ExpectExec().
-		WithArgs(localityToSave.ZipCode, localityCompare.LocalityName, localityCompare.ProvinceName, localityCompare.CountryName).
+		WithArgs(localityToSave.ZipCode, localityCompare.LocalityName, localityCompare.ProvinceID).
 		WillReturnError(errors.New("zipCode duplicated"))
 	ctx = context.Background()
 	actualId, err = localityRepository.Save(ctx, localityCompare)
@@ -71,3 +71,58 @@ func TestCreateConflict(t *testing.T) {
 	assert.Equal(t, localityToSave.ZipCode, localityCompare.ZipCode)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestSaveBatchSkipsExistingZipCodes(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	exists := mock.ExpectPrepare("SELECT id FROM localities WHERE zip_code")
+	insert := mock.ExpectPrepare("INSERT INTO localities")
+	exists.ExpectQuery().WithArgs("6700").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	exists.ExpectQuery().WithArgs("1000").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	insert.ExpectExec().WithArgs("1000", "Capital", 2).WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectCommit()
+
+	localityRepository := NewRepository(db)
+	ids, err := localityRepository.SaveBatch(context.Background(), []domain.Locality{
+		{ZipCode: "6700", LocalityName: "Lujan", ProvinceID: 2},
+		{ZipCode: "1000", LocalityName: "Capital", ProvinceID: 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 8}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveBatchRollsBackOnError(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	exists := mock.ExpectPrepare("SELECT id FROM localities WHERE zip_code")
+	insert := mock.ExpectPrepare("INSERT INTO localities")
+	exists.ExpectQuery().WithArgs("6700").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	insert.ExpectExec().WithArgs("6700", "Lujan", 2).WillReturnError(errors.New("connection lost"))
+	mock.ExpectRollback()
+
+	localityRepository := NewRepository(db)
+	_, err := localityRepository.SaveBatch(context.Background(), []domain.Locality{
+		{ZipCode: "6700", LocalityName: "Lujan", ProvinceID: 2},
+	})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetProvinceByName(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectQuery("SELECT p.id, p.province_name, p.country_id FROM provinces p INNER JOIN countries c ON c.id = p.country_id WHERE c.country_name=\\? AND p.province_name=\\?").
+		WithArgs("Argentina", "Buenos Aires").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "province_name", "country_id"}).AddRow(2, "Buenos Aires", 1))
+	localityRepository := NewRepository(db)
+	province, err := localityRepository.GetProvinceByName(context.Background(), "Argentina", "Buenos Aires")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Province{ID: 2, ProvinceName: "Buenos Aires", CountryID: 1}, province)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,28 +2,54 @@ package locality
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"io"
 
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/carry"
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 )
 
 var (
-	ErrNotFound = errors.New("locality not found")
+	ErrNotFound                = errors.New("locality not found")
+	ErrAlreadyExists           = errors.New("locality already exists")
+	ErrCountryNotFound         = errors.New("country not found")
+	ErrProvinceNotFound        = errors.New("province not found")
+	ErrProvinceCountryMismatch = errors.New("province does not belong to country")
+	ErrHasDependents           = errors.New("locality has sellers or carries")
+	ErrInvalidFormat           = errors.New("invalid import file")
 )
 
 type Service interface {
 	GetAll(ctx context.Context) ([]domain.Locality, error)
+	Get(ctx context.Context, id int) (domain.Locality, error)
 	GetByZipCode(ctx context.Context, zipCode string) (domain.Locality, error)
 	GetSellers(ctx context.Context, l domain.Locality) ([]domain.Seller, error)
 	Save(ctx context.Context, lo domain.Locality) (domain.Locality, error)
 	Exists(ctx context.Context, zipC string) bool
+	Update(ctx context.Context, lo domain.Locality) (domain.Locality, error)
+	Delete(ctx context.Context, id int) error
+	GetReport(ctx context.Context, id int) (domain.LocalityReport, error)
+	Import(ctx context.Context, format string, r io.Reader) (domain.LocalityImportReport, error)
 }
 type service struct {
-	repo Repository
+	repo         Repository
+	carryService carry.Service
 }
 
-func NewService(l Repository) Service {
-	return &service{repo: l}
+func NewService(l Repository, carryService carry.Service) Service {
+	return &service{repo: l, carryService: carryService}
+}
+func (l *service) Get(ctx context.Context, id int) (domain.Locality, error) {
+	lo, err := l.repo.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Locality{}, ErrNotFound
+		}
+		return domain.Locality{}, err
+	}
+	return lo, nil
 }
 func (l *service) GetAll(ctx context.Context) ([]domain.Locality, error) {
 	ls, err := l.repo.GetAll(ctx)
@@ -35,7 +61,10 @@ func (l *service) GetAll(ctx context.Context) ([]domain.Locality, error) {
 func (l *service) Save(ctx context.Context, lo domain.Locality) (domain.Locality, error) {
 	exist := l.repo.Exists(ctx, lo.ZipCode)
 	if exist {
-		return domain.Locality{}, ErrNotFound
+		return domain.Locality{}, ErrAlreadyExists
+	}
+	if err := l.validProvince(ctx, lo); err != nil {
+		return domain.Locality{}, err
 	}
 	p, err := l.repo.Save(ctx, lo)
 	if err != nil {
@@ -44,6 +73,33 @@ func (l *service) Save(ctx context.Context, lo domain.Locality) (domain.Locality
 	lo.ID = p
 	return lo, nil
 }
+func (l *service) Update(ctx context.Context, lo domain.Locality) (domain.Locality, error) {
+	last, err := l.Get(ctx, lo.ID)
+	if err != nil {
+		return domain.Locality{}, err
+	}
+	if lo.ZipCode != last.ZipCode && l.repo.Exists(ctx, lo.ZipCode) {
+		return domain.Locality{}, ErrAlreadyExists
+	}
+	if err := l.validProvince(ctx, lo); err != nil {
+		return domain.Locality{}, err
+	}
+	if err := l.repo.Update(ctx, lo); err != nil {
+		return domain.Locality{}, err
+	}
+	return l.Get(ctx, lo.ID)
+}
+
+// Delete refuses to remove a locality while sellers or carries are located in it.
+func (l *service) Delete(ctx context.Context, id int) error {
+	if _, err := l.Get(ctx, id); err != nil {
+		return err
+	}
+	if l.repo.IsReferenced(ctx, id) {
+		return ErrHasDependents
+	}
+	return l.repo.Delete(ctx, id)
+}
 func (l *service) Exists(ctx context.Context, zipC string) bool {
 	return l.repo.Exists(ctx, zipC)
 }
@@ -58,3 +114,47 @@ func (l *service) GetByZipCode(ctx context.Context, zipCode string) (domain.Loca
 func (s *service) GetSellers(ctx context.Context, l domain.Locality) ([]domain.Seller, error) {
 	return s.repo.GetSellers(ctx, l)
 }
+
+// GetReport counts the sellers and carries located in the locality.
+func (l *service) GetReport(ctx context.Context, id int) (domain.LocalityReport, error) {
+	lo, err := l.Get(ctx, id)
+	if err != nil {
+		return domain.LocalityReport{}, err
+	}
+	sellers, err := l.repo.GetSellers(ctx, lo)
+	if err != nil {
+		return domain.LocalityReport{}, err
+	}
+	report := domain.LocalityReport{
+		LocalityID:   lo.ID,
+		ZipCode:      lo.ZipCode,
+		LocalityName: lo.LocalityName,
+		SellersCount: len(sellers),
+	}
+	// The carries report has no row for a locality without carries.
+	carries, err := l.carryService.GetCarryReportByLocalityId(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return domain.LocalityReport{}, err
+	}
+	report.CarriesCount = carries.CarriesCount
+	return report, nil
+}
+
+// validProvince checks that both the country and the province exist and that
+// the province belongs to the country.
+func (l *service) validProvince(ctx context.Context, lo domain.Locality) error {
+	if !l.repo.ExistsCountry(ctx, lo.CountryID) {
+		return ErrCountryNotFound
+	}
+	countryId, err := l.repo.GetProvinceCountry(ctx, lo.ProvinceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrProvinceNotFound
+		}
+		return err
+	}
+	if countryId != lo.CountryID {
+		return fmt.Errorf("%w: province %d belongs to country %d", ErrProvinceCountryMismatch, lo.ProvinceID, countryId)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package locality
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/carry"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (r *repoM) Save(ctx context.Context, l domain.Locality) (int, error) {
+	args := r.Called(ctx, l)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *repoM) SaveBatch(ctx context.Context, ls []domain.Locality) ([]int, error) {
+	args := r.Called(ctx, ls)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (r *repoM) GetAll(ctx context.Context) ([]domain.Locality, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]domain.Locality), args.Error(1)
+}
+
+func (r *repoM) GetByZipCode(ctx context.Context, zipCode string) (domain.Locality, error) {
+	args := r.Called(ctx, zipCode)
+	return args.Get(0).(domain.Locality), args.Error(1)
+}
+
+func (r *repoM) GetSellers(ctx context.Context, l domain.Locality) ([]domain.Seller, error) {
+	args := r.Called(ctx, l)
+	return args.Get(0).([]domain.Seller), args.Error(1)
+}
+
+func (r *repoM) Exists(ctx context.Context, id string) bool {
+	args := r.Called(ctx, id)
+	return args.Bool(0)
+}
+
+func (r *repoM) ExistsCountry(ctx context.Context, countryId int) bool {
+	args := r.Called(ctx, countryId)
+	return args.Bool(0)
+}
+
+func (r *repoM) GetProvinceCountry(ctx context.Context, provinceId int) (int, error) {
+	args := r.Called(ctx, provinceId)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *repoM) GetProvinceByName(ctx context.Context, countryName, provinceName string) (domain.Province, error) {
+	args := r.Called(ctx, countryName, provinceName)
+	return args.Get(0).(domain.Province), args.Error(1)
+}
+
+func (r *repoM) Get(ctx context.Context, id int) (domain.Locality, error) {
+	args := r.Called(ctx, id)
+	return args.Get(0).(domain.Locality), args.Error(1)
+}
+
+func (r *repoM) IsReferenced(ctx context.Context, id int) bool {
+	args := r.Called(ctx, id)
+	return args.Bool(0)
+}
+
+func (r *repoM) Update(ctx context.Context, l domain.Locality) error {
+	args := r.Called(ctx, l)
+	return args.Error(0)
+}
+
+func (r *repoM) Delete(ctx context.Context, id int) error {
+	args := r.Called(ctx, id)
+	return args.Error(0)
+}
+
+type carryServiceM struct {
+	carry.Service
+	mock.Mock
+}
+
+func (c *carryServiceM) GetCarryReportByLocalityId(ctx context.Context, id int) (domain.CarriesReport, error) {
+	args := c.Called(ctx, id)
+	return args.Get(0).(domain.CarriesReport), args.Error(1)
+}
+
+func newLocality() domain.Locality {
+	return domain.Locality{
+		ZipCode:      "6700",
+		LocalityName: "Lujan",
+		ProvinceID:   2,
+		CountryID:    1,
+	}
+}
+
+func TestServiceSaveOk(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "6700").Return(false)
+	repo.On("ExistsCountry", mock.Anything, 1).Return(true)
+	repo.On("GetProvinceCountry", mock.Anything, 2).Return(1, nil)
+	repo.On("Save", mock.Anything, newLocality()).Return(3, nil)
+	s := NewService(repo, new(carryServiceM))
+	result, err := s.Save(context.Background(), newLocality())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.ID)
+}
+
+func TestServiceSaveDuplicatedZipCode(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "6700").Return(true)
+	s := NewService(repo, new(carryServiceM))
+	_, err := s.Save(context.Background(), newLocality())
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestServiceSaveCountryNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "6700").Return(false)
+	repo.On("ExistsCountry", mock.Anything, 1).Return(false)
+	s := NewService(repo, new(carryServiceM))
+	_, err := s.Save(context.Background(), newLocality())
+	assert.ErrorIs(t, err, ErrCountryNotFound)
+}
+
+func TestServiceSaveProvinceNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "6700").Return(false)
+	repo.On("ExistsCountry", mock.Anything, 1).Return(true)
+	repo.On("GetProvinceCountry", mock.Anything, 2).Return(0, sql.ErrNoRows)
+	s := NewService(repo, new(carryServiceM))
+	_, err := s.Save(context.Background(), newLocality())
+	assert.ErrorIs(t, err, ErrProvinceNotFound)
+}
+
+func TestServiceSaveProvinceOfOtherCountry(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "6700").Return(false)
+	repo.On("ExistsCountry", mock.Anything, 1).Return(true)
+	repo.On("GetProvinceCountry", mock.Anything, 2).Return(4, nil)
+	s := NewService(repo, new(carryServiceM))
+	_, err := s.Save(context.Background(), newLocality())
+	assert.ErrorIs(t, err, ErrProvinceCountryMismatch)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestServiceUpdateZipCodeTaken(t *testing.T) {
+	last := newLocality()
+	last.ID = 3
+	changed := last
+	changed.ZipCode = "1000"
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 3).Return(last, nil)
+	repo.On("Exists", mock.Anything, "1000").Return(true)
+	s := NewService(repo, new(carryServiceM))
+	_, err := s.Update(context.Background(), changed)
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestServiceDeleteWithDependents(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 3).Return(newLocality(), nil)
+	repo.On("IsReferenced", mock.Anything, 3).Return(true)
+	s := NewService(repo, new(carryServiceM))
+	err := s.Delete(context.Background(), 3)
+	assert.ErrorIs(t, err, ErrHasDependents)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestServiceGetReport(t *testing.T) {
+	lo := newLocality()
+	lo.ID = 3
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 3).Return(lo, nil)
+	repo.On("GetSellers", mock.Anything, lo).Return([]domain.Seller{{ID: 1}, {ID: 2}}, nil)
+	carries := new(carryServiceM)
+	carries.On("GetCarryReportByLocalityId", mock.Anything, 3).Return(domain.CarriesReport{LocalityId: 3, CarriesCount: 4}, nil)
+	s := NewService(repo, carries)
+	report, err := s.GetReport(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LocalityReport{LocalityID: 3, ZipCode: "6700", LocalityName: "Lujan", SellersCount: 2, CarriesCount: 4}, report)
+}
+
+func TestServiceGetReportWithoutCarries(t *testing.T) {
+	lo := newLocality()
+	lo.ID = 3
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 3).Return(lo, nil)
+	repo.On("GetSellers", mock.Anything, lo).Return([]domain.Seller{}, nil)
+	carries := new(carryServiceM)
+	carries.On("GetCarryReportByLocalityId", mock.Anything, 3).Return(domain.CarriesReport{}, sql.ErrNoRows)
+	s := NewService(repo, carries)
+	report, err := s.GetReport(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.SellersCount)
+	assert.Equal(t, 0, report.CarriesCount)
+}
+
+func TestServiceGetReportNonExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 9).Return(domain.Locality{}, sql.ErrNoRows)
+	s := NewService(repo, new(carryServiceM))
+	_, err := s.GetReport(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -11,6 +11,7 @@ type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Product, error)
 	Get(ctx context.Context, id int) (domain.Product, error)
 	Exists(ctx context.Context, productCode string) bool
+	ExistsProductType(ctx context.Context, productTypeId int) bool
 	Save(ctx context.Context, p domain.Product) (int, error)
 	Update(ctx context.Context, p domain.Product) error
 	Delete(ctx context.Context, id int) error
@@ -71,6 +72,13 @@ func (r *repository) Exists(ctx context.Context, productCode string) bool {
 	return err == nil
 }
 
+func (r *repository) ExistsProductType(ctx context.Context, productTypeId int) bool {
+	query := "SELECT id FROM product_types WHERE id=?;"
+	row := r.db.QueryRow(query, productTypeId)
+	err := row.Scan(&productTypeId)
+	return err == nil
+}
+
 func (r *repository) Save(ctx context.Context, p domain.Product) (int, error) {
 	query := "INSERT INTO products(description,expiration_rate,freezing_rate,height,lenght,netweight,product_code,recommended_freezing_temperature,width,id_product_type,id_seller) VALUES (?,?,?,?,?,?,?,?,?,?,?)"
 	stmt, err := r.db.Prepare(query)
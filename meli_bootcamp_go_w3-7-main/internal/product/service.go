@@ -11,6 +11,7 @@ var (
 	ErrNotFound               = errors.New("product not found")
 	ErrAlreadyExists          = errors.New("product already exists")
 	ErrProductRecordsNotFound = errors.New("product records not found for the provided product id")
+	ErrProductTypeNotFound    = errors.New("product type not found for the provided product type id")
 )
 
 type Service interface {
@@ -55,6 +56,9 @@ func (s *service) Save(ctx context.Context, p domain.Product) (domain.Product, e
 	if s.productRepository.Exists(ctx, p.ProductCode) {
 		return domain.Product{}, ErrAlreadyExists
 	}
+	if !s.productRepository.ExistsProductType(ctx, p.ProductTypeID) {
+		return domain.Product{}, ErrProductTypeNotFound
+	}
 	newProductId, err := s.productRepository.Save(ctx, p)
 	if err != nil {
 		return domain.Product{}, err
@@ -73,6 +77,9 @@ func (s *service) Update(ctx context.Context, id int, productPatch domain.Produc
 		return domain.Product{}, err
 	}
 	updatedProduct := updateFields(productToUpdate, productPatch)
+	if updatedProduct.ProductTypeID != productToUpdate.ProductTypeID && !s.productRepository.ExistsProductType(ctx, updatedProduct.ProductTypeID) {
+		return domain.Product{}, ErrProductTypeNotFound
+	}
 	err = s.productRepository.Update(ctx, updatedProduct)
 	if err != nil {
 		return domain.Product{}, err
@@ -29,6 +29,11 @@ func (m *dbMock) Exists(ctx context.Context, productCode string) bool {
 	return args.Bool(0)
 }
 
+func (m *dbMock) ExistsProductType(ctx context.Context, productTypeId int) bool {
+	args := m.Called(ctx, productTypeId)
+	return args.Bool(0)
+}
+
 func (m *dbMock) Save(ctx context.Context, product domain.Product) (int, error) {
 	args := m.Called(ctx, product)
 	return args.Int(0), args.Error(1)
@@ -83,6 +88,7 @@ func TestCreateOk(t *testing.T) {
 
 	productRepository := new(dbMock)
 	productRepository.On("Exists", mock.Anything, productToCreate.ProductCode).Return(false)
+	productRepository.On("ExistsProductType", mock.Anything, productToCreate.ProductTypeID).Return(true)
 	productRepository.On("Save", mock.Anything, productToCreate).Return(1, nil)
 	productService := NewService(productRepository)
 	ctx := context.Background()
@@ -127,6 +133,30 @@ func TestCreateConflict(t *testing.T) {
 	assert.Equal(t, expectedServiceError, actualServiceError)
 }
 
+func TestCreateProductTypeNotFound(t *testing.T) {
+	// ARRANGE
+	productToCreate := domain.Product{
+		Description:   "Yogurt",
+		ProductCode:   "PROD08",
+		ProductTypeID: 9,
+		SellerID:      4,
+	}
+
+	productRepository := new(dbMock)
+	productRepository.On("Exists", mock.Anything, productToCreate.ProductCode).Return(false)
+	productRepository.On("ExistsProductType", mock.Anything, productToCreate.ProductTypeID).Return(false)
+	productService := NewService(productRepository)
+	ctx := context.Background()
+
+	// ACT
+	actualServiceResult, actualServiceError := productService.Save(ctx, productToCreate)
+
+	// ASSERT
+	assert.Equal(t, domain.Product{}, actualServiceResult)
+	assert.ErrorIs(t, actualServiceError, ErrProductTypeNotFound)
+	productRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
 func TestFindAll(t *testing.T) {
 	// ARRANGE
 	mockedRepoResult := []domain.Product{}
@@ -290,6 +320,7 @@ func TestUpdateExistent(t *testing.T) {
 
 	productRepository := new(dbMock)
 	productRepository.On("Get", mock.Anything, productPatchToApplyId).Return(mockedRepoResultGet, nil)
+	productRepository.On("ExistsProductType", mock.Anything, patchedProductToUpdate.ProductTypeID).Return(true)
 	productRepository.On("Update", mock.Anything, patchedProductToUpdate).Return(nil)
 	productService := NewService(productRepository)
 	ctx := context.Background()
@@ -0,0 +1,27 @@
+package productBatches
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunExpiryJob blocks and writes off the expired batches right away and then every
+// interval, so they can no longer be picked. It returns when ctx is cancelled.
+func RunExpiryJob(ctx context.Context, s Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		blocked, err := s.BlockExpired(ctx)
+		if err != nil {
+			log.Printf("blocking expired product batches: %v", err)
+		} else if blocked > 0 {
+			log.Printf("blocked %d expired product batches", blocked)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,8 +3,11 @@ package productBatches
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/stockMovements"
 )
 
 // Repository encapsulates the storage of a section.
@@ -13,8 +16,18 @@ type Repository interface {
 	GetQuantity(ctx context.Context, quant int) (domain.ProductBatches, error)
 	Exists(ctx context.Context, cid int) bool
 	Save(ctx context.Context, s domain.ProductBatches) (int, error)
+	GetAllReportProducts(ctx context.Context) ([]domain.ReportProducts, error)
+	GetReportBySectionIdProducts(ctx context.Context, sectionId int) (domain.ReportProducts, error)
+	Pick(ctx context.Context, req domain.PickRequest) ([]domain.PickLine, error)
+	GetExpiring(ctx context.Context, days, warehouseId int) ([]domain.ExpiringBatch, error)
+	BlockExpired(ctx context.Context) (int, error)
 }
 
+const (
+	selectProductBatchesQuery = "SELECT id, batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id FROM product_batches"
+	QueryReportProducts       = "SELECT s.id, s.section_number, COALESCE(SUM(pb.current_quantity), 0) FROM sections s LEFT JOIN product_batches pb ON pb.section_id = s.id"
+)
+
 type repository struct {
 	db *sql.DB
 }
@@ -25,14 +38,54 @@ func NewRepository(db *sql.DB) Repository {
 	}
 }
 
+// Save inserts the batch, occupies its quantity in the section and records the inbound
+// movement inside a single transaction, so the capacity check and the update cannot
+// interleave with another batch.
 func (r *repository) Save(ctx context.Context, s domain.ProductBatches) (int, error) {
-	query := "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
-	stmt, err := r.db.Prepare(query)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var currentCapacity, maximumCapacity, sectionProductType, sectionTemperature, sectionMinimumTemperature int
+	query := "SELECT current_capacity, maximum_capacity, id_product_type, current_temperature, minimum_temperature FROM sections WHERE id=? FOR UPDATE;"
+	err = tx.QueryRowContext(ctx, query, s.SectionId).Scan(&currentCapacity, &maximumCapacity, &sectionProductType, &sectionTemperature, &sectionMinimumTemperature)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrSectionNotFound
+		}
 		return 0, err
 	}
+	if currentCapacity+s.CurrentQuantity > maximumCapacity {
+		return 0, fmt.Errorf("%w: %d + %d > %d", ErrSectionCapacityExceeded, currentCapacity, s.CurrentQuantity, maximumCapacity)
+	}
 
-	res, err := stmt.Exec(&s.BatchNumber, &s.CurrentQuantity, &s.CurrentTemperature, &s.DueDate, &s.InitialQuantity, &s.ManufacturingDate, &s.ManufacturingHour, &s.MinimumTemperature, &s.ProductId, &s.SectionId)
+	var productType int
+	var recomFreezTemp float32
+	query = "SELECT id_product_type, recommended_freezing_temperature FROM products WHERE id=?;"
+	err = tx.QueryRowContext(ctx, query, s.ProductId).Scan(&productType, &recomFreezTemp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrProductNotFound
+		}
+		return 0, err
+	}
+	if productType != sectionProductType {
+		return 0, fmt.Errorf("%w: %d != %d", ErrProductTypeMismatch, productType, sectionProductType)
+	}
+	if err := checkTemperature(s, sectionTemperature, sectionMinimumTemperature, recomFreezTemp); err != nil {
+		return 0, err
+	}
+
+	query = "INSERT INTO product_batches (batch_number, current_quantity, current_temperature, due_date, initial_quantity, manufacturing_date, manufacturing_hour, minimum_temperature, product_id, section_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, &s.BatchNumber, &s.CurrentQuantity, &s.CurrentTemperature, &s.DueDate, &s.InitialQuantity, &s.ManufacturingDate, &s.ManufacturingHour, &s.MinimumTemperature, &s.ProductId, &s.SectionId)
 	if err != nil {
 		return 0, err
 	}
@@ -42,6 +95,25 @@ func (r *repository) Save(ctx context.Context, s domain.ProductBatches) (int, er
 		return 0, err
 	}
 
+	query = "UPDATE sections SET current_capacity=current_capacity+? WHERE id=?;"
+	if _, err := tx.ExecContext(ctx, query, s.CurrentQuantity, s.SectionId); err != nil {
+		return 0, err
+	}
+
+	err = stockMovements.Record(ctx, tx, domain.StockMovement{
+		Type:           stockMovements.TypeInbound,
+		ProductBatchID: int(id),
+		SectionID:      s.SectionId,
+		Quantity:       s.CurrentQuantity,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
 	return int(id), nil
 }
 
@@ -53,10 +125,10 @@ func (r *repository) Exists(ctx context.Context, batchNumber int) bool {
 }
 
 func (r *repository) GetBySectionNumber(ctx context.Context, sectionNumber int) (domain.Section, error) {
-	query := "SELECT * FROM sections WHERE section_number=?"
+	query := "SELECT id, section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type FROM sections WHERE section_number=?;"
 	row := r.db.QueryRow(query, sectionNumber)
 	s := domain.Section{}
-	err := row.Scan(&s.SectionNumber)
+	err := row.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
 	if err != nil {
 		return domain.Section{}, err
 	}
@@ -64,12 +136,224 @@ func (r *repository) GetBySectionNumber(ctx context.Context, sectionNumber int)
 }
 
 func (r *repository) GetQuantity(ctx context.Context, quant int) (domain.ProductBatches, error) {
-	query := "SELECT * FROM product_batches WHERE current_quantity=?"
+	query := selectProductBatchesQuery + " WHERE current_quantity=?;"
 	row := r.db.QueryRow(query, quant)
 	s := domain.ProductBatches{}
-	err := row.Scan(&s.CurrentQuantity, &s.ProductId)
+	err := row.Scan(&s.ID, &s.BatchNumber, &s.CurrentQuantity, &s.CurrentTemperature, &s.DueDate, &s.InitialQuantity, &s.ManufacturingDate, &s.ManufacturingHour, &s.MinimumTemperature, &s.ProductId, &s.SectionId)
 	if err != nil {
 		return domain.ProductBatches{}, err
 	}
 	return s, nil
 }
+
+func (r *repository) GetAllReportProducts(ctx context.Context) ([]domain.ReportProducts, error) {
+	query := QueryReportProducts + " GROUP BY s.id, s.section_number;"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []domain.ReportProducts
+
+	for rows.Next() {
+		rp := domain.ReportProducts{}
+		if err := rows.Scan(&rp.SectionID, &rp.SectionNumber, &rp.ProductsCount); err != nil {
+			return nil, err
+		}
+		reports = append(reports, rp)
+	}
+
+	return reports, nil
+}
+
+func (r *repository) GetReportBySectionIdProducts(ctx context.Context, sectionId int) (domain.ReportProducts, error) {
+	query := QueryReportProducts + " WHERE s.id=? GROUP BY s.id, s.section_number;"
+	row := r.db.QueryRow(query, sectionId)
+	rp := domain.ReportProducts{}
+	err := row.Scan(&rp.SectionID, &rp.SectionNumber, &rp.ProductsCount)
+	if err != nil {
+		return domain.ReportProducts{}, err
+	}
+	return rp, nil
+}
+
+// Pick takes the requested quantity of the product from its non blocked, non expired
+// batches, earliest due date first, releasing the capacity they occupied in their sections
+// and recording a pick movement per batch. Either the whole quantity is picked or nothing is.
+func (r *repository) Pick(ctx context.Context, req domain.PickRequest) ([]domain.PickLine, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT pb.id, pb.batch_number, pb.section_id, pb.due_date, pb.current_quantity FROM product_batches pb INNER JOIN sections s ON s.id = pb.section_id WHERE pb.product_id=? AND pb.current_quantity > 0 AND pb.blocked = 0 AND pb.due_date >= CURDATE()"
+	args := []interface{}{req.ProductID}
+	if req.WarehouseID != 0 {
+		query += " AND s.warehouse_id=?"
+		args = append(args, req.WarehouseID)
+	}
+	rows, err := tx.QueryContext(ctx, query+" ORDER BY pb.due_date, pb.id FOR UPDATE;", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []domain.PickLine
+	remaining := req.Quantity
+	for rows.Next() && remaining > 0 {
+		line := domain.PickLine{}
+		var available int
+		if err := rows.Scan(&line.ProductBatchID, &line.BatchNumber, &line.SectionID, &line.DueDate, &available); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		line.Quantity = available
+		if remaining < available {
+			line.Quantity = remaining
+		}
+		remaining -= line.Quantity
+		lines = append(lines, line)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("%w: se pidieron %d y hay %d disponibles", ErrInsufficientStock, req.Quantity, req.Quantity-remaining)
+	}
+
+	for _, line := range lines {
+		query = "UPDATE product_batches SET current_quantity=current_quantity-? WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, line.Quantity, line.ProductBatchID); err != nil {
+			return nil, err
+		}
+		query = "UPDATE sections SET current_capacity=current_capacity-? WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, line.Quantity, line.SectionID); err != nil {
+			return nil, err
+		}
+		err = stockMovements.Record(ctx, tx, domain.StockMovement{
+			Type:              stockMovements.TypePick,
+			ProductBatchID:    line.ProductBatchID,
+			SectionID:         line.SectionID,
+			Quantity:          -line.Quantity,
+			EmployeeID:        req.EmployeeID,
+			ReferenceDocument: req.ReferenceDocument,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// GetExpiring lists the batches with stock that expire within the next days days,
+// including the ones already expired, ordered by warehouse, section and due date.
+func (r *repository) GetExpiring(ctx context.Context, days, warehouseId int) ([]domain.ExpiringBatch, error) {
+	query := "SELECT pb.id, pb.batch_number, pb.product_id, pb.section_id, s.section_number, s.warehouse_id, pb.current_quantity, pb.due_date, DATEDIFF(pb.due_date, CURDATE()), pb.blocked FROM product_batches pb INNER JOIN sections s ON s.id = pb.section_id WHERE pb.current_quantity > 0 AND pb.due_date < DATE_ADD(CURDATE(), INTERVAL ? DAY)"
+	args := []interface{}{days + 1}
+	if warehouseId != 0 {
+		query += " AND s.warehouse_id=?"
+		args = append(args, warehouseId)
+	}
+	rows, err := r.db.Query(query+" ORDER BY s.warehouse_id, pb.section_id, pb.due_date, pb.id;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []domain.ExpiringBatch
+
+	for rows.Next() {
+		b := domain.ExpiringBatch{}
+		if err := rows.Scan(&b.ProductBatchID, &b.BatchNumber, &b.ProductID, &b.SectionID, &b.SectionNumber, &b.WarehouseID, &b.CurrentQuantity, &b.DueDate, &b.DaysLeft, &b.Blocked); err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+
+	return batches, nil
+}
+
+// BlockExpired blocks the expired batches and writes off the quantity they still hold,
+// releasing it from their sections and recording a write-off movement per batch, in a
+// single transaction.
+func (r *repository) BlockExpired(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id, section_id, current_quantity FROM product_batches WHERE blocked=0 AND due_date < CURDATE() FOR UPDATE;"
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var expired []domain.ProductBatches
+	for rows.Next() {
+		b := domain.ProductBatches{}
+		if err := rows.Scan(&b.ID, &b.SectionId, &b.CurrentQuantity); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		expired = append(expired, b)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+
+	for _, b := range expired {
+		query = "UPDATE product_batches SET blocked=1, current_quantity=0 WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, b.ID); err != nil {
+			return 0, err
+		}
+		if b.CurrentQuantity == 0 {
+			continue
+		}
+		query = "UPDATE sections SET current_capacity=current_capacity-? WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, b.CurrentQuantity, b.SectionId); err != nil {
+			return 0, err
+		}
+		err = stockMovements.Record(ctx, tx, domain.StockMovement{
+			Type:           stockMovements.TypeWriteOff,
+			ProductBatchID: b.ID,
+			SectionID:      b.SectionId,
+			Quantity:       -b.CurrentQuantity,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(expired), nil
+}
+
+// checkTemperature verifies the cold chain of a batch entering a section. The batch
+// must not be warmer than the product's recommended freezing temperature nor colder
+// than its own minimum_temperature, and the section's current temperature must stay
+// between the warmer of both minimums (batch and section), so neither is undercut, and
+// that recommended temperature.
+func checkTemperature(b domain.ProductBatches, sectionTemperature, sectionMinimumTemperature int, recomFreezTemp float32) error {
+	batchTemperature := float32(b.CurrentTemperature)
+	if batchTemperature < b.MinimumTemperature || batchTemperature > recomFreezTemp {
+		return fmt.Errorf("%w: el batch %d llega a %d grados y debe estar entre %.2f y %.2f grados", ErrTemperatureOutOfRange, b.BatchNumber, b.CurrentTemperature, b.MinimumTemperature, recomFreezTemp)
+	}
+	minTemperature := b.MinimumTemperature
+	if float32(sectionMinimumTemperature) > minTemperature {
+		minTemperature = float32(sectionMinimumTemperature)
+	}
+	if float32(sectionTemperature) < minTemperature || float32(sectionTemperature) > recomFreezTemp {
+		return fmt.Errorf("%w: la seccion %d esta a %d grados y el batch %d del producto %d requiere entre %.2f y %.2f grados", ErrTemperatureOutOfRange, b.SectionId, sectionTemperature, b.BatchNumber, b.ProductId, minTemperature, recomFreezTemp)
+	}
+	return nil
+}
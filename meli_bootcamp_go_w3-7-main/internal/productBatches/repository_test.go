@@ -12,22 +12,43 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCreateOkProductBatches(t *testing.T) {
-	ProdBatchesToSave := domain.ProductBatches{
+func newProductBatchesToSave() domain.ProductBatches {
+	return domain.ProductBatches{
 		BatchNumber:        111,
 		CurrentQuantity:    200,
-		CurrentTemperature: 20,
+		CurrentTemperature: -18,
 		DueDate:            "2022-04-04",
 		InitialQuantity:    10,
 		ManufacturingDate:  "2020-04-04",
 		ManufacturingHour:  10,
-		MinimumTemperature: 5,
+		MinimumTemperature: -25,
 		ProductId:          1,
 		SectionId:          1,
 	}
+}
+
+func expectPlacementChecks(mock sqlmock.Sqlmock, currentCapacity, maximumCapacity, sectionProductType, productType int) {
+	expectTemperatureChecks(mock, currentCapacity, maximumCapacity, sectionProductType, productType, -18, -22, -15)
+}
+
+func expectTemperatureChecks(mock sqlmock.Sqlmock, currentCapacity, maximumCapacity, sectionProductType, productType, sectionTemperature, sectionMinimumTemperature int, recomFreezTemp float32) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT current_capacity, maximum_capacity, id_product_type, current_temperature, minimum_temperature FROM sections").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "id_product_type", "current_temperature", "minimum_temperature"}).
+			AddRow(currentCapacity, maximumCapacity, sectionProductType, sectionTemperature, sectionMinimumTemperature))
+	mock.ExpectQuery("SELECT id_product_type, recommended_freezing_temperature FROM products").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id_product_type", "recommended_freezing_temperature"}).
+			AddRow(productType, recomFreezTemp))
+}
+
+func TestCreateOkProductBatches(t *testing.T) {
+	ProdBatchesToSave := newProductBatchesToSave()
 	db, mock, sqlMockErr := sqlmock.New()
 	assert.Nil(t, sqlMockErr)
 	defer db.Close()
+	expectPlacementChecks(mock, 100, 300, 2, 2)
 	mock.
 		ExpectPrepare("INSERT INTO product_batches").
 		ExpectExec().
@@ -43,6 +64,13 @@ func TestCreateOkProductBatches(t *testing.T) {
 			ProdBatchesToSave.SectionId).
 		WillReturnResult(sqlmock.NewResult(1, 1)).
 		WillReturnError(nil)
+	mock.ExpectExec("UPDATE sections SET current_capacity").
+		WithArgs(ProdBatchesToSave.CurrentQuantity, ProdBatchesToSave.SectionId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO stock_movements").
+		WithArgs("inbound", 1, ProdBatchesToSave.SectionId, ProdBatchesToSave.CurrentQuantity, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 	productBatchesRepository := NewRepository(db)
 	actualId, err := productBatchesRepository.Save(context.Background(), ProdBatchesToSave)
 	assert.Nil(t, err)
@@ -51,70 +79,244 @@ func TestCreateOkProductBatches(t *testing.T) {
 }
 
 func TestCreateConflictProductBatches(t *testing.T) {
-	ProdBatchesToSave := domain.ProductBatches{
-		BatchNumber:        111,
-		CurrentQuantity:    200,
-		CurrentTemperature: 20,
-		DueDate:            "2022-04-04",
-		InitialQuantity:    10,
-		ManufacturingDate:  "2020-04-04",
-		ManufacturingHour:  10,
-		MinimumTemperature: 5,
-		ProductId:          1,
-		SectionId:          1,
-	}
+	ProdBatchesToSave := newProductBatchesToSave()
 	db, mock, sqlMockErr := sqlmock.New()
 	assert.Nil(t, sqlMockErr)
 	defer db.Close()
+	expectPlacementChecks(mock, 100, 300, 2, 2)
 	mock.
 		ExpectPrepare("INSERT INTO product_batches").
 		ExpectExec().
-		WithArgs(ProdBatchesToSave.BatchNumber,
-			ProdBatchesToSave.CurrentQuantity,
-			ProdBatchesToSave.CurrentTemperature,
-			ProdBatchesToSave.DueDate,
-			ProdBatchesToSave.InitialQuantity,
-			ProdBatchesToSave.ManufacturingDate,
-			ProdBatchesToSave.ManufacturingHour,
-			ProdBatchesToSave.MinimumTemperature,
-			ProdBatchesToSave.ProductId,
-			ProdBatchesToSave.SectionId).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	productBatchesRepository := NewRepository(db)
-	ctx := context.Background()
-	actualId, err := productBatchesRepository.Save(ctx, ProdBatchesToSave)
-	assert.Nil(t, err)
-	assert.Equal(t, 1, actualId)
-	prodBatchesCompare := domain.ProductBatches{
-		BatchNumber:        111,
-		CurrentQuantity:    200,
-		CurrentTemperature: 20,
-		DueDate:            "2022-04-04",
-		InitialQuantity:    10,
-		ManufacturingDate:  "2020-04-04",
-		ManufacturingHour:  10,
-		MinimumTemperature: 5,
-		ProductId:          1,
-		SectionId:          1,
-	}
-	mock.
-		ExpectPrepare("INSERT INTO product_batches").
-		ExpectExec().
-		WithArgs(prodBatchesCompare.BatchNumber,
-			prodBatchesCompare.CurrentQuantity,
-			prodBatchesCompare.CurrentTemperature,
-			prodBatchesCompare.DueDate,
-			prodBatchesCompare.InitialQuantity,
-			prodBatchesCompare.ManufacturingDate,
-			prodBatchesCompare.ManufacturingHour,
-			prodBatchesCompare.MinimumTemperature,
-			prodBatchesCompare.ProductId,
-			prodBatchesCompare.SectionId).
 		WillReturnError(errors.New("batch Number duplicated"))
-	ctx = context.Background()
-	actualId, err = productBatchesRepository.Save(ctx, prodBatchesCompare)
+	mock.ExpectRollback()
+	productBatchesRepository := NewRepository(db)
+	actualId, err := productBatchesRepository.Save(context.Background(), ProdBatchesToSave)
 	assert.NotNil(t, err)
 	assert.Equal(t, 0, actualId)
-	assert.Equal(t, ProdBatchesToSave.BatchNumber, prodBatchesCompare.BatchNumber)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateSectionNotFoundProductBatches(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT current_capacity, maximum_capacity, id_product_type, current_temperature, minimum_temperature FROM sections").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "id_product_type", "current_temperature", "minimum_temperature"}))
+	mock.ExpectRollback()
+	productBatchesRepository := NewRepository(db)
+	_, err := productBatchesRepository.Save(context.Background(), newProductBatchesToSave())
+	assert.ErrorIs(t, err, ErrSectionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateCapacityExceededProductBatches(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT current_capacity, maximum_capacity, id_product_type, current_temperature, minimum_temperature FROM sections").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "id_product_type", "current_temperature", "minimum_temperature"}).AddRow(150, 300, 2, -18, -22))
+	mock.ExpectRollback()
+	productBatchesRepository := NewRepository(db)
+	_, err := productBatchesRepository.Save(context.Background(), newProductBatchesToSave())
+	assert.ErrorIs(t, err, ErrSectionCapacityExceeded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateProductTypeMismatchProductBatches(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	expectPlacementChecks(mock, 100, 300, 2, 3)
+	mock.ExpectRollback()
+	productBatchesRepository := NewRepository(db)
+	_, err := productBatchesRepository.Save(context.Background(), newProductBatchesToSave())
+	assert.ErrorIs(t, err, ErrProductTypeMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateTemperatureOutOfRangeProductBatches(t *testing.T) {
+	tests := []struct {
+		name                      string
+		batchTemperature          int
+		sectionTemperature        int
+		sectionMinimumTemperature int
+		message                   string
+	}{
+		{"section warmer than recommended", -18, -10, -22, "la seccion 1 esta a -10 grados"},
+		{"section colder than its minimum", -18, -24, -22, "entre -22.00 y -15.00 grados"},
+		{"section colder than the batch minimum", -18, -30, -40, "entre -25.00 y -15.00 grados"},
+		{"batch arrives warm", -5, -18, -22, "el batch 111 llega a -5 grados"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, sqlMockErr := sqlmock.New()
+			assert.Nil(t, sqlMockErr)
+			defer db.Close()
+			expectTemperatureChecks(mock, 100, 300, 2, 2, tt.sectionTemperature, tt.sectionMinimumTemperature, -15)
+			mock.ExpectRollback()
+			batch := newProductBatchesToSave()
+			batch.CurrentTemperature = tt.batchTemperature
+			productBatchesRepository := NewRepository(db)
+			_, err := productBatchesRepository.Save(context.Background(), batch)
+			assert.ErrorIs(t, err, ErrTemperatureOutOfRange)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetAllReportProducts(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "section_number", "products_count"}).
+		AddRow(1, 10, 250).
+		AddRow(2, 20, 0)
+	mock.ExpectQuery("SELECT s.id, s.section_number, COALESCE").WillReturnRows(rows)
+	productBatchesRepository := NewRepository(db)
+	reports, err := productBatchesRepository.GetAllReportProducts(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.ReportProducts{
+		{SectionID: 1, SectionNumber: 10, ProductsCount: 250},
+		{SectionID: 2, SectionNumber: 20, ProductsCount: 0},
+	}, reports)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetReportBySectionIdProducts(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "section_number", "products_count"}).AddRow(1, 10, 250)
+	mock.ExpectQuery("WHERE s.id").WithArgs(1).WillReturnRows(rows)
+	productBatchesRepository := NewRepository(db)
+	report, err := productBatchesRepository.GetReportBySectionIdProducts(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, domain.ReportProducts{SectionID: 1, SectionNumber: 10, ProductsCount: 250}, report)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetReportBySectionIdProductsNonExistent(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectQuery("WHERE s.id").WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"id", "section_number", "products_count"}))
+	productBatchesService := NewService(NewRepository(db))
+	_, err := productBatchesService.GetReportBySectionIdProducts(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrSectionNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPickProductBatchesFEFO(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("ORDER BY pb.due_date, pb.id FOR UPDATE").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "batch_number", "section_id", "due_date", "current_quantity"}).
+			AddRow(4, 111, 1, "2022-04-04", 30).
+			AddRow(6, 112, 2, "2022-05-01", 100))
+	mock.ExpectExec("UPDATE product_batches SET current_quantity").WithArgs(30, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE sections SET current_capacity").WithArgs(30, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO stock_movements").WithArgs("pick", 4, 1, -30, 3, "order#1").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE product_batches SET current_quantity").WithArgs(20, 6).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE sections SET current_capacity").WithArgs(20, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO stock_movements").WithArgs("pick", 6, 2, -20, 3, "order#1").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+	productBatchesService := NewService(NewRepository(db))
+	pickList, err := productBatchesService.Pick(context.Background(), domain.PickRequest{ProductID: 1, Quantity: 50, WarehouseID: 2, EmployeeID: 3, ReferenceDocument: "order#1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.PickLine{
+		{ProductBatchID: 4, BatchNumber: 111, SectionID: 1, DueDate: "2022-04-04", Quantity: 30},
+		{ProductBatchID: 6, BatchNumber: 112, SectionID: 2, DueDate: "2022-05-01", Quantity: 20},
+	}, pickList.Lines)
+	assert.Equal(t, 50, pickList.Quantity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPickProductBatchesInsufficientStock(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("ORDER BY pb.due_date, pb.id FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "batch_number", "section_id", "due_date", "current_quantity"}).
+			AddRow(4, 111, 1, "2022-04-04", 30))
+	mock.ExpectRollback()
+	productBatchesService := NewService(NewRepository(db))
+	_, err := productBatchesService.Pick(context.Background(), domain.PickRequest{ProductID: 1, Quantity: 50})
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPickProductBatchesInvalidQuantity(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	productBatchesService := NewService(NewRepository(db))
+	_, err := productBatchesService.Pick(context.Background(), domain.PickRequest{ProductID: 1})
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExpiringProductBatches(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	columns := []string{"id", "batch_number", "product_id", "section_id", "section_number", "warehouse_id", "current_quantity", "due_date", "days_left", "blocked"}
+	mock.ExpectQuery("DATE_ADD\\(CURDATE\\(\\), INTERVAL \\? DAY\\)").
+		WithArgs(8).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, 100, 1, 1, 10, 1, 5, "2022-04-01", -3, true).
+			AddRow(2, 101, 1, 1, 10, 1, 5, "2022-04-05", 1, false).
+			AddRow(3, 102, 2, 1, 10, 1, 5, "2022-04-06", 2, false).
+			AddRow(4, 103, 2, 2, 20, 1, 5, "2022-04-06", 2, false).
+			AddRow(5, 104, 3, 3, 30, 2, 5, "2022-04-10", 6, false))
+	productBatchesService := NewService(NewRepository(db))
+	report, err := productBatchesService.GetExpiring(context.Background(), 7, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, report.Days)
+	assert.Len(t, report.Expired, 1)
+	assert.Equal(t, 1, report.Expired[0].ProductBatchID)
+	assert.Len(t, report.Warehouses, 2)
+	assert.Len(t, report.Warehouses[0].Sections, 2)
+	assert.Len(t, report.Warehouses[0].Sections[0].Batches, 2)
+	assert.Equal(t, 20, report.Warehouses[0].Sections[1].SectionNumber)
+	assert.Equal(t, 5, report.Warehouses[1].Sections[0].Batches[0].ProductBatchID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlockExpiredProductBatches(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, section_id, current_quantity FROM product_batches WHERE blocked=0 AND due_date < CURDATE\\(\\) FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_id", "current_quantity"}).
+			AddRow(1, 1, 30).
+			AddRow(2, 1, 0).
+			AddRow(3, 2, 5))
+	mock.ExpectExec("UPDATE product_batches SET blocked=1, current_quantity=0").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE sections SET current_capacity=current_capacity-\\?").WithArgs(30, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO stock_movements").
+		WithArgs("write-off", 1, 1, -30, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("UPDATE product_batches SET blocked=1, current_quantity=0").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE product_batches SET blocked=1, current_quantity=0").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE sections SET current_capacity=current_capacity-\\?").WithArgs(5, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO stock_movements").
+		WithArgs("write-off", 3, 2, -5, nil, nil).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+	productBatchesRepository := NewRepository(db)
+	blocked, err := productBatchesRepository.BlockExpired(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, blocked)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
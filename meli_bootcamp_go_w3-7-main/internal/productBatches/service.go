@@ -2,6 +2,7 @@ package productBatches
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
@@ -9,8 +10,16 @@ import (
 
 // Errors
 var (
-	ErrNotFound      = errors.New("el product batches no fue encontrada")
-	ErrAlreadyExists = errors.New("el product batches  ya existe")
+	ErrNotFound                = errors.New("el product batches no fue encontrada")
+	ErrAlreadyExists           = errors.New("el product batches  ya existe")
+	ErrSectionNotFound         = errors.New("la seccion no fue encontrada")
+	ErrProductNotFound         = errors.New("el producto no fue encontrado")
+	ErrSectionCapacityExceeded = errors.New("la seccion no tiene capacidad suficiente")
+	ErrProductTypeMismatch     = errors.New("el product type del producto no coincide con el de la seccion")
+	ErrTemperatureOutOfRange   = errors.New("la temperatura de la seccion esta fuera del rango recomendado del producto")
+	ErrInvalidQuantity         = errors.New("la cantidad a pickear debe ser mayor a cero")
+	ErrInsufficientStock       = errors.New("no hay stock suficiente del producto")
+	ErrInvalidDays             = errors.New("la cantidad de dias no puede ser negativa")
 )
 
 type Service interface {
@@ -18,6 +27,11 @@ type Service interface {
 	GetQuantity(ctx context.Context, quant int) (domain.ProductBatches, error)
 	Save(ctx context.Context, sec domain.ProductBatches) (domain.ProductBatches, error)
 	Exists(ctx context.Context, batchNumber int) bool
+	GetAllReportProducts(ctx context.Context) ([]domain.ReportProducts, error)
+	GetReportBySectionIdProducts(ctx context.Context, sectionId int) (domain.ReportProducts, error)
+	Pick(ctx context.Context, req domain.PickRequest) (domain.PickList, error)
+	GetExpiring(ctx context.Context, days, warehouseId int) (domain.ExpiringReport, error)
+	BlockExpired(ctx context.Context) (int, error)
 }
 
 type service struct {
@@ -62,3 +76,75 @@ func (s *service) GetQuantity(ctx context.Context, sec int) (domain.ProductBatch
 	}
 	return p, nil
 }
+
+func (s *service) GetAllReportProducts(ctx context.Context) ([]domain.ReportProducts, error) {
+	return s.productBatchesRepository.GetAllReportProducts(ctx)
+}
+
+func (s *service) GetReportBySectionIdProducts(ctx context.Context, sectionId int) (domain.ReportProducts, error) {
+	report, err := s.productBatchesRepository.GetReportBySectionIdProducts(ctx, sectionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ReportProducts{}, ErrSectionNotFound
+		}
+		return domain.ReportProducts{}, err
+	}
+	return report, nil
+}
+
+func (s *service) Pick(ctx context.Context, req domain.PickRequest) (domain.PickList, error) {
+	if req.Quantity <= 0 {
+		return domain.PickList{}, ErrInvalidQuantity
+	}
+	lines, err := s.productBatchesRepository.Pick(ctx, req)
+	if err != nil {
+		return domain.PickList{}, err
+	}
+	return domain.PickList{
+		ProductID:   req.ProductID,
+		WarehouseID: req.WarehouseID,
+		Quantity:    req.Quantity,
+		Lines:       lines,
+	}, nil
+}
+
+// GetExpiring splits the batches returned by the repository into the expired bucket and
+// the ones still due within days, grouped by warehouse and section.
+func (s *service) GetExpiring(ctx context.Context, days, warehouseId int) (domain.ExpiringReport, error) {
+	if days < 0 {
+		return domain.ExpiringReport{}, ErrInvalidDays
+	}
+	batches, err := s.productBatchesRepository.GetExpiring(ctx, days, warehouseId)
+	if err != nil {
+		return domain.ExpiringReport{}, err
+	}
+
+	report := domain.ExpiringReport{
+		Days:       days,
+		Expired:    []domain.ExpiringBatch{},
+		Warehouses: []domain.ExpiringWarehouse{},
+	}
+	for _, b := range batches {
+		if b.DaysLeft < 0 {
+			report.Expired = append(report.Expired, b)
+			continue
+		}
+		w := len(report.Warehouses) - 1
+		if w < 0 || report.Warehouses[w].WarehouseID != b.WarehouseID {
+			report.Warehouses = append(report.Warehouses, domain.ExpiringWarehouse{WarehouseID: b.WarehouseID})
+			w++
+		}
+		warehouse := &report.Warehouses[w]
+		sec := len(warehouse.Sections) - 1
+		if sec < 0 || warehouse.Sections[sec].SectionID != b.SectionID {
+			warehouse.Sections = append(warehouse.Sections, domain.ExpiringSection{SectionID: b.SectionID, SectionNumber: b.SectionNumber})
+			sec++
+		}
+		warehouse.Sections[sec].Batches = append(warehouse.Sections[sec].Batches, b)
+	}
+	return report, nil
+}
+
+func (s *service) BlockExpired(ctx context.Context) (int, error) {
+	return s.productBatchesRepository.BlockExpired(ctx)
+}
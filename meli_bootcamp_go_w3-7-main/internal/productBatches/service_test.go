@@ -0,0 +1,104 @@
+package productBatches
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	Repository
+	mock.Mock
+}
+
+func (r *repoM) GetExpiring(ctx context.Context, days, warehouseId int) ([]domain.ExpiringBatch, error) {
+	args := r.Called(ctx, days, warehouseId)
+	return args.Get(0).([]domain.ExpiringBatch), args.Error(1)
+}
+
+func (r *repoM) BlockExpired(ctx context.Context) (int, error) {
+	args := r.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func TestGetExpiringNegativeDays(t *testing.T) {
+	repo := new(repoM)
+	s := NewService(repo)
+	_, err := s.GetExpiring(context.Background(), -1, 0)
+	assert.ErrorIs(t, err, ErrInvalidDays)
+	repo.AssertNotCalled(t, "GetExpiring", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetExpiringZeroDaysKeepsDueToday(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetExpiring", mock.Anything, 0, 2).Return([]domain.ExpiringBatch{
+		{ProductBatchID: 1, SectionID: 3, WarehouseID: 2, DaysLeft: -1},
+		{ProductBatchID: 2, SectionID: 3, WarehouseID: 2, DaysLeft: 0},
+	}, nil)
+	s := NewService(repo)
+	report, err := s.GetExpiring(context.Background(), 0, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, report.Days)
+	assert.Len(t, report.Expired, 1)
+	assert.Equal(t, 1, report.Expired[0].ProductBatchID)
+	assert.Len(t, report.Warehouses, 1)
+	assert.Equal(t, 2, report.Warehouses[0].Sections[0].Batches[0].ProductBatchID)
+	repo.AssertExpectations(t)
+}
+
+func TestGetExpiringGroupsByWarehouseAndSection(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetExpiring", mock.Anything, 7, 0).Return([]domain.ExpiringBatch{
+		{ProductBatchID: 1, SectionID: 1, SectionNumber: 10, WarehouseID: 1, DaysLeft: -3},
+		{ProductBatchID: 2, SectionID: 1, SectionNumber: 10, WarehouseID: 1, DaysLeft: 1},
+		{ProductBatchID: 3, SectionID: 1, SectionNumber: 10, WarehouseID: 1, DaysLeft: 2},
+		{ProductBatchID: 4, SectionID: 2, SectionNumber: 20, WarehouseID: 1, DaysLeft: 7},
+		{ProductBatchID: 5, SectionID: 3, SectionNumber: 30, WarehouseID: 2, DaysLeft: -1},
+		{ProductBatchID: 6, SectionID: 3, SectionNumber: 30, WarehouseID: 2, DaysLeft: 6},
+	}, nil)
+	s := NewService(repo)
+	report, err := s.GetExpiring(context.Background(), 7, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 5}, []int{report.Expired[0].ProductBatchID, report.Expired[1].ProductBatchID})
+	assert.Len(t, report.Warehouses, 2)
+	assert.Equal(t, 1, report.Warehouses[0].WarehouseID)
+	assert.Len(t, report.Warehouses[0].Sections, 2)
+	assert.Equal(t, 10, report.Warehouses[0].Sections[0].SectionNumber)
+	assert.Len(t, report.Warehouses[0].Sections[0].Batches, 2)
+	assert.Equal(t, 4, report.Warehouses[0].Sections[1].Batches[0].ProductBatchID)
+	assert.Equal(t, 2, report.Warehouses[1].WarehouseID)
+	assert.Len(t, report.Warehouses[1].Sections, 1)
+	assert.Equal(t, 6, report.Warehouses[1].Sections[0].Batches[0].ProductBatchID)
+	repo.AssertExpectations(t)
+}
+
+func TestGetExpiringEmpty(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetExpiring", mock.Anything, 3, 0).Return([]domain.ExpiringBatch(nil), nil)
+	s := NewService(repo)
+	report, err := s.GetExpiring(context.Background(), 3, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.ExpiringBatch{}, report.Expired)
+	assert.Equal(t, []domain.ExpiringWarehouse{}, report.Warehouses)
+}
+
+func TestGetExpiringRepositoryError(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetExpiring", mock.Anything, 3, 0).Return([]domain.ExpiringBatch(nil), errors.New("db down"))
+	s := NewService(repo)
+	_, err := s.GetExpiring(context.Background(), 3, 0)
+	assert.EqualError(t, err, "db down")
+}
+
+func TestRunExpiryJobBlocksUntilCancelled(t *testing.T) {
+	repo := new(repoM)
+	ctx, cancel := context.WithCancel(context.Background())
+	repo.On("BlockExpired", mock.Anything).Return(2, nil).Run(func(mock.Arguments) { cancel() })
+	RunExpiryJob(ctx, NewService(repo), time.Hour)
+	repo.AssertNumberOfCalls(t, "BlockExpired", 1)
+}
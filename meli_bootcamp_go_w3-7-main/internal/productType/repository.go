@@ -0,0 +1,142 @@
+package productType
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Repository encapsulates the storage of a product type.
+type Repository interface {
+	GetAll(ctx context.Context) ([]domain.ProductType, error)
+	Get(ctx context.Context, id int) (domain.ProductType, error)
+	Exists(ctx context.Context, description string) bool
+	InUse(ctx context.Context, id int) bool
+	Save(ctx context.Context, p domain.ProductType) (int, error)
+	Update(ctx context.Context, p domain.ProductType) error
+	Delete(ctx context.Context, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	query := "SELECT id, description FROM product_types;"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var productTypes []domain.ProductType
+
+	for rows.Next() {
+		p := domain.ProductType{}
+		_ = rows.Scan(&p.ID, &p.Description)
+		productTypes = append(productTypes, p)
+	}
+
+	return productTypes, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.ProductType, error) {
+	query := "SELECT id, description FROM product_types WHERE id=?;"
+	row := r.db.QueryRow(query, id)
+	p := domain.ProductType{}
+	err := row.Scan(&p.ID, &p.Description)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+
+	return p, nil
+}
+
+func (r *repository) Exists(ctx context.Context, description string) bool {
+	query := "SELECT description FROM product_types WHERE description=?;"
+	row := r.db.QueryRow(query, description)
+	err := row.Scan(&description)
+	return err == nil
+}
+
+// InUse reports whether a product or a section still references the product type.
+func (r *repository) InUse(ctx context.Context, id int) bool {
+	query := "SELECT id FROM products WHERE id_product_type=? UNION ALL SELECT id FROM sections WHERE id_product_type=? LIMIT 1;"
+	row := r.db.QueryRow(query, id, id)
+	err := row.Scan(&id)
+	return err == nil
+}
+
+func (r *repository) Save(ctx context.Context, p domain.ProductType) (int, error) {
+	query := "INSERT INTO product_types (description) VALUES (?);"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(p.Description)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) Update(ctx context.Context, p domain.ProductType) error {
+	query := "UPDATE product_types SET description=? WHERE id=?;"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(p.Description, p.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM product_types WHERE id=?;"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
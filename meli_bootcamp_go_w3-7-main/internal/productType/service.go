@@ -0,0 +1,86 @@
+package productType
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Errors
+var (
+	ErrNotFound      = errors.New("product type not found")
+	ErrAlreadyExists = errors.New("product type already exists")
+	ErrInUse         = errors.New("product type is used by products or sections")
+)
+
+type Service interface {
+	GetAll(ctx context.Context) ([]domain.ProductType, error)
+	Get(ctx context.Context, id int) (domain.ProductType, error)
+	Save(ctx context.Context, p domain.ProductType) (domain.ProductType, error)
+	Update(ctx context.Context, p domain.ProductType) (domain.ProductType, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.ProductType, error) {
+	p, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ProductType{}, ErrNotFound
+		}
+		return domain.ProductType{}, err
+	}
+	return p, nil
+}
+
+func (s *service) Save(ctx context.Context, p domain.ProductType) (domain.ProductType, error) {
+	if s.repository.Exists(ctx, p.Description) {
+		return domain.ProductType{}, ErrAlreadyExists
+	}
+	id, err := s.repository.Save(ctx, p)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+	p.ID = id
+	return p, nil
+}
+
+func (s *service) Update(ctx context.Context, p domain.ProductType) (domain.ProductType, error) {
+	last, err := s.Get(ctx, p.ID)
+	if err != nil {
+		return domain.ProductType{}, err
+	}
+	if p.Description != last.Description && s.repository.Exists(ctx, p.Description) {
+		return domain.ProductType{}, ErrAlreadyExists
+	}
+	if err := s.repository.Update(ctx, p); err != nil {
+		return domain.ProductType{}, err
+	}
+	return p, nil
+}
+
+// Delete refuses to remove a product type while products or sections still reference it.
+func (s *service) Delete(ctx context.Context, id int) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if s.repository.InUse(ctx, id) {
+		return ErrInUse
+	}
+	return s.repository.Delete(ctx, id)
+}
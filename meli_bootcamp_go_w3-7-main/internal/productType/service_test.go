@@ -0,0 +1,126 @@
+package productType
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (r *repoM) GetAll(ctx context.Context) ([]domain.ProductType, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]domain.ProductType), args.Error(1)
+}
+
+func (r *repoM) Get(ctx context.Context, id int) (domain.ProductType, error) {
+	args := r.Called(ctx, id)
+	return args.Get(0).(domain.ProductType), args.Error(1)
+}
+
+func (r *repoM) Exists(ctx context.Context, description string) bool {
+	args := r.Called(ctx, description)
+	return args.Bool(0)
+}
+
+func (r *repoM) Save(ctx context.Context, p domain.ProductType) (int, error) {
+	args := r.Called(ctx, p)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *repoM) Update(ctx context.Context, p domain.ProductType) error {
+	args := r.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (r *repoM) Delete(ctx context.Context, id int) error {
+	args := r.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (r *repoM) InUse(ctx context.Context, id int) bool {
+	args := r.Called(ctx, id)
+	return args.Bool(0)
+}
+
+func TestCreateOk(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "Congelados").Return(false)
+	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
+	s := NewService(repo)
+	result, err := s.Save(context.Background(), domain.ProductType{Description: "Congelados"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ProductType{ID: 1, Description: "Congelados"}, result)
+}
+
+func TestCreateConflict(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, "Congelados").Return(true)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.ProductType{Description: "Congelados"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestFindByIdNonExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.ProductType{}, sql.ErrNoRows)
+	s := NewService(repo)
+	_, err := s.Get(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.ProductType{ID: 1, Description: "Frescos"}, nil)
+	repo.On("Exists", mock.Anything, "Congelados").Return(false)
+	repo.On("Update", mock.Anything, domain.ProductType{ID: 1, Description: "Congelados"}).Return(nil)
+	s := NewService(repo)
+	result, err := s.Update(context.Background(), domain.ProductType{ID: 1, Description: "Congelados"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Congelados", result.Description)
+}
+
+func TestUpdateConflict(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.ProductType{ID: 1, Description: "Frescos"}, nil)
+	repo.On("Exists", mock.Anything, "Congelados").Return(true)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.ProductType{ID: 1, Description: "Congelados"})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestDeleteNonExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.ProductType{}, sql.ErrNoRows)
+	s := NewService(repo)
+	err := s.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteInUse(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.ProductType{ID: 1, Description: "Congelados"}, nil)
+	repo.On("InUse", mock.Anything, 1).Return(true)
+	s := NewService(repo)
+	err := s.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrInUse)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteUnused(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.ProductType{ID: 1, Description: "Congelados"}, nil)
+	repo.On("InUse", mock.Anything, 1).Return(false)
+	repo.On("Delete", mock.Anything, 1).Return(nil)
+	s := NewService(repo)
+	assert.NoError(t, s.Delete(context.Background(), 1))
+}
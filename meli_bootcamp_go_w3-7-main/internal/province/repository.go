@@ -0,0 +1,147 @@
+package province
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Repository encapsulates the storage of a province.
+type Repository interface {
+	GetAll(ctx context.Context, countryId int) ([]domain.Province, error)
+	Get(ctx context.Context, id int) (domain.Province, error)
+	Exists(ctx context.Context, countryId int, provinceName string) bool
+	ExistsCountry(ctx context.Context, countryId int) bool
+	HasLocalities(ctx context.Context, id int) bool
+	Save(ctx context.Context, p domain.Province) (int, error)
+	Update(ctx context.Context, p domain.Province) error
+	Delete(ctx context.Context, id int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// GetAll returns every province, or only those of the country when countryId
+// is not zero.
+func (r *repository) GetAll(ctx context.Context, countryId int) ([]domain.Province, error) {
+	query := "SELECT id, province_name, country_id FROM provinces WHERE 1=1"
+	args := []interface{}{}
+	if countryId != 0 {
+		query += " AND country_id=?"
+		args = append(args, countryId)
+	}
+	rows, err := r.db.Query(query+" ORDER BY province_name;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var provinces []domain.Province
+
+	for rows.Next() {
+		p := domain.Province{}
+		_ = rows.Scan(&p.ID, &p.ProvinceName, &p.CountryID)
+		provinces = append(provinces, p)
+	}
+
+	return provinces, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.Province, error) {
+	query := "SELECT id, province_name, country_id FROM provinces WHERE id=?;"
+	row := r.db.QueryRow(query, id)
+	p := domain.Province{}
+	err := row.Scan(&p.ID, &p.ProvinceName, &p.CountryID)
+	if err != nil {
+		return domain.Province{}, err
+	}
+
+	return p, nil
+}
+
+func (r *repository) Exists(ctx context.Context, countryId int, provinceName string) bool {
+	query := "SELECT province_name FROM provinces WHERE country_id=? AND province_name=?;"
+	row := r.db.QueryRow(query, countryId, provinceName)
+	err := row.Scan(&provinceName)
+	return err == nil
+}
+
+func (r *repository) ExistsCountry(ctx context.Context, countryId int) bool {
+	query := "SELECT id FROM countries WHERE id=?;"
+	row := r.db.QueryRow(query, countryId)
+	err := row.Scan(&countryId)
+	return err == nil
+}
+
+func (r *repository) HasLocalities(ctx context.Context, id int) bool {
+	query := "SELECT id FROM localities WHERE province_id=? LIMIT 1;"
+	row := r.db.QueryRow(query, id)
+	err := row.Scan(&id)
+	return err == nil
+}
+
+func (r *repository) Save(ctx context.Context, p domain.Province) (int, error) {
+	query := "INSERT INTO provinces (province_name, country_id) VALUES (?, ?);"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(p.ProvinceName, p.CountryID)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) Update(ctx context.Context, p domain.Province) error {
+	query := "UPDATE provinces SET province_name=?, country_id=? WHERE id=?;"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(p.ProvinceName, p.CountryID, p.ID)
+	return err
+}
+
+func (r *repository) Delete(ctx context.Context, id int) error {
+	query := "DELETE FROM provinces WHERE id=?;"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package province
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Errors
+var (
+	ErrNotFound        = errors.New("province not found")
+	ErrAlreadyExists   = errors.New("province already exists")
+	ErrCountryNotFound = errors.New("country not found")
+	ErrHasLocalities   = errors.New("province has localities")
+)
+
+type Service interface {
+	GetAll(ctx context.Context, countryId int) ([]domain.Province, error)
+	Get(ctx context.Context, id int) (domain.Province, error)
+	Save(ctx context.Context, p domain.Province) (domain.Province, error)
+	Update(ctx context.Context, p domain.Province) (domain.Province, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context, countryId int) ([]domain.Province, error) {
+	return s.repository.GetAll(ctx, countryId)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.Province, error) {
+	p, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Province{}, ErrNotFound
+		}
+		return domain.Province{}, err
+	}
+	return p, nil
+}
+
+// Save requires an existing country; province names are unique per country.
+func (s *service) Save(ctx context.Context, p domain.Province) (domain.Province, error) {
+	if !s.repository.ExistsCountry(ctx, p.CountryID) {
+		return domain.Province{}, ErrCountryNotFound
+	}
+	if s.repository.Exists(ctx, p.CountryID, p.ProvinceName) {
+		return domain.Province{}, ErrAlreadyExists
+	}
+	id, err := s.repository.Save(ctx, p)
+	if err != nil {
+		return domain.Province{}, err
+	}
+	p.ID = id
+	return p, nil
+}
+
+func (s *service) Update(ctx context.Context, p domain.Province) (domain.Province, error) {
+	last, err := s.Get(ctx, p.ID)
+	if err != nil {
+		return domain.Province{}, err
+	}
+	if p.CountryID != last.CountryID && !s.repository.ExistsCountry(ctx, p.CountryID) {
+		return domain.Province{}, ErrCountryNotFound
+	}
+	if (p.CountryID != last.CountryID || p.ProvinceName != last.ProvinceName) && s.repository.Exists(ctx, p.CountryID, p.ProvinceName) {
+		return domain.Province{}, ErrAlreadyExists
+	}
+	if err := s.repository.Update(ctx, p); err != nil {
+		return domain.Province{}, err
+	}
+	return p, nil
+}
+
+// Delete refuses to remove a province while localities still reference it.
+func (s *service) Delete(ctx context.Context, id int) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	if s.repository.HasLocalities(ctx, id) {
+		return ErrHasLocalities
+	}
+	return s.repository.Delete(ctx, id)
+}
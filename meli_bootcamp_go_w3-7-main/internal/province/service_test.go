@@ -0,0 +1,110 @@
+package province
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (r *repoM) GetAll(ctx context.Context, countryId int) ([]domain.Province, error) {
+	args := r.Called(ctx, countryId)
+	return args.Get(0).([]domain.Province), args.Error(1)
+}
+
+func (r *repoM) Get(ctx context.Context, id int) (domain.Province, error) {
+	args := r.Called(ctx, id)
+	return args.Get(0).(domain.Province), args.Error(1)
+}
+
+func (r *repoM) Exists(ctx context.Context, countryId int, provinceName string) bool {
+	args := r.Called(ctx, countryId, provinceName)
+	return args.Bool(0)
+}
+
+func (r *repoM) ExistsCountry(ctx context.Context, countryId int) bool {
+	args := r.Called(ctx, countryId)
+	return args.Bool(0)
+}
+
+func (r *repoM) HasLocalities(ctx context.Context, id int) bool {
+	args := r.Called(ctx, id)
+	return args.Bool(0)
+}
+
+func (r *repoM) Save(ctx context.Context, p domain.Province) (int, error) {
+	args := r.Called(ctx, p)
+	return args.Int(0), args.Error(1)
+}
+
+func (r *repoM) Update(ctx context.Context, p domain.Province) error {
+	args := r.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (r *repoM) Delete(ctx context.Context, id int) error {
+	args := r.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateOk(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCountry", mock.Anything, 1).Return(true)
+	repo.On("Exists", mock.Anything, 1, "Buenos Aires").Return(false)
+	repo.On("Save", mock.Anything, mock.Anything).Return(2, nil)
+	s := NewService(repo)
+	result, err := s.Save(context.Background(), domain.Province{ProvinceName: "Buenos Aires", CountryID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Province{ID: 2, ProvinceName: "Buenos Aires", CountryID: 1}, result)
+}
+
+func TestCreateCountryNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCountry", mock.Anything, 7).Return(false)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Province{ProvinceName: "Buenos Aires", CountryID: 7})
+	assert.ErrorIs(t, err, ErrCountryNotFound)
+}
+
+func TestCreateConflict(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCountry", mock.Anything, 1).Return(true)
+	repo.On("Exists", mock.Anything, 1, "Buenos Aires").Return(true)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Province{ProvinceName: "Buenos Aires", CountryID: 1})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestUpdateMovesToUnknownCountry(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 2).Return(domain.Province{ID: 2, ProvinceName: "Buenos Aires", CountryID: 1}, nil)
+	repo.On("ExistsCountry", mock.Anything, 7).Return(false)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Province{ID: 2, ProvinceName: "Buenos Aires", CountryID: 7})
+	assert.ErrorIs(t, err, ErrCountryNotFound)
+}
+
+func TestDeleteNonExistent(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 9).Return(domain.Province{}, sql.ErrNoRows)
+	s := NewService(repo)
+	err := s.Delete(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDeleteWithLocalities(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 2).Return(domain.Province{ID: 2, ProvinceName: "Buenos Aires", CountryID: 1}, nil)
+	repo.On("HasLocalities", mock.Anything, 2).Return(true)
+	s := NewService(repo)
+	err := s.Delete(context.Background(), 2)
+	assert.ErrorIs(t, err, ErrHasLocalities)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
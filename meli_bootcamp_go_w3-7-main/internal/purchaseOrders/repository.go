@@ -0,0 +1,158 @@
+package purchaseOrders
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Repository encapsulates the storage of a purchase order.
+type Repository interface {
+	GetAll(ctx context.Context) ([]domain.PurchaseOrders, error)
+	Get(ctx context.Context, id int) (domain.PurchaseOrders, error)
+	Exists(ctx context.Context, orderNumber string) bool
+	Save(ctx context.Context, p domain.PurchaseOrders) (int, error)
+	ExistsBuyer(ctx context.Context, buyerId int) bool
+	ExistsProductRecord(ctx context.Context, productRecordId int) bool
+	ExistsOrderStatus(ctx context.Context, orderStatusId int) bool
+	UpdateStatus(ctx context.Context, id int, fromStatusId int, orderStatusId int) error
+}
+
+const (
+	selectPurchaseOrdersQuery = "SELECT id, order_number, order_date, tracking_code, buyer_id, product_record_id, order_status_id FROM purchase_orders"
+	dateTimeLayout            = "2006-01-02 15:04:05"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	rows, err := r.db.Query(selectPurchaseOrdersQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var purchaseOrders []domain.PurchaseOrders
+
+	for rows.Next() {
+		p, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		purchaseOrders = append(purchaseOrders, p)
+	}
+
+	return purchaseOrders, nil
+}
+
+func (r *repository) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	row := r.db.QueryRow(selectPurchaseOrdersQuery+" WHERE id=?;", id)
+	return scanPurchaseOrder(row)
+}
+
+func (r *repository) Exists(ctx context.Context, orderNumber string) bool {
+	query := "SELECT order_number FROM purchase_orders WHERE order_number=?;"
+	row := r.db.QueryRow(query, orderNumber)
+	err := row.Scan(&orderNumber)
+	return err == nil
+}
+
+func (r *repository) Save(ctx context.Context, p domain.PurchaseOrders) (int, error) {
+	query := "INSERT INTO purchase_orders(order_number,order_date,tracking_code,buyer_id,product_record_id,order_status_id) VALUES (?,?,?,?,?,?)"
+	stmt, err := r.db.Prepare(query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.Exec(p.OrderNumber, p.OrderDate, p.TrackingCode, p.BuyerID, p.ProductRecordID, p.OrderStatusID)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) ExistsBuyer(ctx context.Context, buyerId int) bool {
+	query := "SELECT id FROM buyers WHERE id=?;"
+	row := r.db.QueryRow(query, buyerId)
+	err := row.Scan(&buyerId)
+	return err == nil
+}
+
+func (r *repository) ExistsProductRecord(ctx context.Context, productRecordId int) bool {
+	query := "SELECT id FROM product_records WHERE id=?;"
+	row := r.db.QueryRow(query, productRecordId)
+	err := row.Scan(&productRecordId)
+	return err == nil
+}
+
+func (r *repository) ExistsOrderStatus(ctx context.Context, orderStatusId int) bool {
+	query := "SELECT id FROM order_statuses WHERE id=?;"
+	row := r.db.QueryRow(query, orderStatusId)
+	err := row.Scan(&orderStatusId)
+	return err == nil
+}
+
+// UpdateStatus moves the purchase order to orderStatusId only while it is still
+// in fromStatusId, so a concurrent change made after the transition was checked
+// makes it fail with ErrInvalidStatusTransition.
+func (r *repository) UpdateStatus(ctx context.Context, id int, fromStatusId int, orderStatusId int) error {
+	query := "UPDATE purchase_orders SET order_status_id=? WHERE id=? AND order_status_id=?"
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, orderStatusId, id, fromStatusId)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affect < 1 {
+		return fmt.Errorf("%w: order status is no longer %d", ErrInvalidStatusTransition, fromStatusId)
+	}
+
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPurchaseOrder reads order_date as a string, since the mysql driver doesn't parse datetimes into time.Time by default.
+func scanPurchaseOrder(s scanner) (domain.PurchaseOrders, error) {
+	p := domain.PurchaseOrders{}
+	var orderDate string
+	err := s.Scan(&p.ID, &p.OrderNumber, &orderDate, &p.TrackingCode, &p.BuyerID, &p.ProductRecordID, &p.OrderStatusID)
+	if err != nil {
+		return domain.PurchaseOrders{}, err
+	}
+	p.OrderDate, err = time.Parse(dateTimeLayout, orderDate)
+	if err != nil {
+		return domain.PurchaseOrders{}, err
+	}
+	return p, nil
+}
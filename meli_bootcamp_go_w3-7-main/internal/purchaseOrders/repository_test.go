@@ -0,0 +1,23 @@
+package purchaseOrders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryUpdateStatusOnlyFromReadStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPrepare("UPDATE purchase_orders SET order_status_id=\\? WHERE id=\\? AND order_status_id=\\?").
+		ExpectExec().WithArgs(OrderStatusCancelled, 1, OrderStatusPicking).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewRepository(db)
+	err = repo.UpdateStatus(context.Background(), 1, OrderStatusPicking, OrderStatusCancelled)
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
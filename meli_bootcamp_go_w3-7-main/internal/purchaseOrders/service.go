@@ -0,0 +1,117 @@
+package purchaseOrders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Errors
+var (
+	ErrNotFound                = errors.New("purchase order not found")
+	ErrAlreadyExists           = errors.New("a purchase order with that order_number already exists")
+	ErrBuyerNotFound           = errors.New("the buyer doesn't exist")
+	ErrProductRecordNotFound   = errors.New("the product record doesn't exist")
+	ErrOrderStatusNotFound     = errors.New("the order status doesn't exist")
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+)
+
+// Order statuses, the ids match the rows seeded into order_statuses by db.sql.
+const (
+	OrderStatusPending   = 1
+	OrderStatusPicking   = 2
+	OrderStatusShipped   = 3
+	OrderStatusDelivered = 4
+	OrderStatusCancelled = 5
+)
+
+// statusTransitions lists, for every status, the statuses an order can move to.
+// Delivered and cancelled are final.
+var statusTransitions = map[int][]int{
+	OrderStatusPending: {OrderStatusPicking, OrderStatusCancelled},
+	OrderStatusPicking: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped: {OrderStatusDelivered},
+}
+
+type Service interface {
+	GetAll(ctx context.Context) ([]domain.PurchaseOrders, error)
+	Get(ctx context.Context, id int) (domain.PurchaseOrders, error)
+	Save(ctx context.Context, p domain.PurchaseOrders) (domain.PurchaseOrders, error)
+	UpdateStatus(ctx context.Context, id int, orderStatusId int) (domain.PurchaseOrders, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	return s.repository.GetAll(ctx)
+}
+
+func (s *service) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	p, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.PurchaseOrders{}, ErrNotFound
+		}
+		return domain.PurchaseOrders{}, err
+	}
+	return p, nil
+}
+
+func (s *service) Save(ctx context.Context, p domain.PurchaseOrders) (domain.PurchaseOrders, error) {
+	if !s.repository.ExistsBuyer(ctx, p.BuyerID) {
+		return domain.PurchaseOrders{}, ErrBuyerNotFound
+	}
+	if !s.repository.ExistsProductRecord(ctx, p.ProductRecordID) {
+		return domain.PurchaseOrders{}, ErrProductRecordNotFound
+	}
+	if !s.repository.ExistsOrderStatus(ctx, p.OrderStatusID) {
+		return domain.PurchaseOrders{}, ErrOrderStatusNotFound
+	}
+	if s.repository.Exists(ctx, p.OrderNumber) {
+		return domain.PurchaseOrders{}, ErrAlreadyExists
+	}
+	id, err := s.repository.Save(ctx, p)
+	if err != nil {
+		return domain.PurchaseOrders{}, err
+	}
+	p.ID = id
+	return p, nil
+}
+
+func (s *service) UpdateStatus(ctx context.Context, id int, orderStatusId int) (domain.PurchaseOrders, error) {
+	p, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.PurchaseOrders{}, err
+	}
+	if !s.repository.ExistsOrderStatus(ctx, orderStatusId) {
+		return domain.PurchaseOrders{}, ErrOrderStatusNotFound
+	}
+	if !canTransition(p.OrderStatusID, orderStatusId) {
+		return domain.PurchaseOrders{}, fmt.Errorf("%w: %d -> %d", ErrInvalidStatusTransition, p.OrderStatusID, orderStatusId)
+	}
+	if err := s.repository.UpdateStatus(ctx, id, p.OrderStatusID, orderStatusId); err != nil {
+		return domain.PurchaseOrders{}, err
+	}
+	p.OrderStatusID = orderStatusId
+	return p, nil
+}
+
+func canTransition(from int, to int) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
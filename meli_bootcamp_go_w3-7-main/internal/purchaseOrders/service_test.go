@@ -0,0 +1,169 @@
+package purchaseOrders
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type dbPOMock struct {
+	mock.Mock
+}
+
+func (m *dbPOMock) GetAll(ctx context.Context) ([]domain.PurchaseOrders, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *dbPOMock) Get(ctx context.Context, id int) (domain.PurchaseOrders, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.PurchaseOrders), args.Error(1)
+}
+
+func (m *dbPOMock) Exists(ctx context.Context, orderNumber string) bool {
+	args := m.Called(ctx, orderNumber)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) Save(ctx context.Context, p domain.PurchaseOrders) (int, error) {
+	args := m.Called(ctx, p)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *dbPOMock) ExistsBuyer(ctx context.Context, buyerId int) bool {
+	args := m.Called(ctx, buyerId)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) ExistsProductRecord(ctx context.Context, productRecordId int) bool {
+	args := m.Called(ctx, productRecordId)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) ExistsOrderStatus(ctx context.Context, orderStatusId int) bool {
+	args := m.Called(ctx, orderStatusId)
+	return args.Bool(0)
+}
+
+func (m *dbPOMock) UpdateStatus(ctx context.Context, id int, fromStatusId int, orderStatusId int) error {
+	args := m.Called(ctx, id, fromStatusId, orderStatusId)
+	return args.Error(0)
+}
+
+func newPurchaseOrder() domain.PurchaseOrders {
+	orderDate, _ := time.Parse("2006-01-02", "2022-01-06")
+	return domain.PurchaseOrders{
+		OrderNumber:     "order#1",
+		OrderDate:       orderDate,
+		TrackingCode:    "abscf123",
+		BuyerID:         1,
+		ProductRecordID: 1,
+		OrderStatusID:   1,
+	}
+}
+
+func TestCreateOk(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("ExistsBuyer", mock.Anything, 1).Return(true)
+	repo.On("ExistsProductRecord", mock.Anything, 1).Return(true)
+	repo.On("ExistsOrderStatus", mock.Anything, 1).Return(true)
+	repo.On("Exists", mock.Anything, "order#1").Return(false)
+	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
+	s := NewService(repo)
+	result, err := s.Save(context.Background(), newPurchaseOrder())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+}
+
+func TestCreateBuyerNotFound(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("ExistsBuyer", mock.Anything, 1).Return(false)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), newPurchaseOrder())
+	assert.ErrorIs(t, err, ErrBuyerNotFound)
+}
+
+func TestCreateProductRecordNotFound(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("ExistsBuyer", mock.Anything, 1).Return(true)
+	repo.On("ExistsProductRecord", mock.Anything, 1).Return(false)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), newPurchaseOrder())
+	assert.ErrorIs(t, err, ErrProductRecordNotFound)
+}
+
+func TestCreateConflict(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("ExistsBuyer", mock.Anything, 1).Return(true)
+	repo.On("ExistsProductRecord", mock.Anything, 1).Return(true)
+	repo.On("ExistsOrderStatus", mock.Anything, 1).Return(true)
+	repo.On("Exists", mock.Anything, "order#1").Return(true)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), newPurchaseOrder())
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestUpdateStatusOk(t *testing.T) {
+	stored := newPurchaseOrder()
+	stored.ID = 1
+	stored.OrderStatusID = OrderStatusShipped
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 1).Return(stored, nil)
+	repo.On("ExistsOrderStatus", mock.Anything, OrderStatusDelivered).Return(true)
+	repo.On("UpdateStatus", mock.Anything, 1, OrderStatusShipped, OrderStatusDelivered).Return(nil)
+	s := NewService(repo)
+	result, err := s.UpdateStatus(context.Background(), 1, OrderStatusDelivered)
+	assert.NoError(t, err)
+	assert.Equal(t, OrderStatusDelivered, result.OrderStatusID)
+}
+
+func TestUpdateStatusInvalidTransition(t *testing.T) {
+	stored := newPurchaseOrder()
+	stored.ID = 1
+	stored.OrderStatusID = OrderStatusDelivered
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 1).Return(stored, nil)
+	repo.On("ExistsOrderStatus", mock.Anything, OrderStatusPending).Return(true)
+	s := NewService(repo)
+	_, err := s.UpdateStatus(context.Background(), 1, OrderStatusPending)
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateStatusUnknownStatus(t *testing.T) {
+	stored := newPurchaseOrder()
+	stored.ID = 1
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 1).Return(stored, nil)
+	repo.On("ExistsOrderStatus", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	_, err := s.UpdateStatus(context.Background(), 1, 9)
+	assert.ErrorIs(t, err, ErrOrderStatusNotFound)
+}
+
+func TestUpdateStatusNonExistent(t *testing.T) {
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 1).Return(domain.PurchaseOrders{}, sql.ErrNoRows)
+	s := NewService(repo)
+	_, err := s.UpdateStatus(context.Background(), 1, OrderStatusPicking)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestUpdateStatusChangedConcurrently(t *testing.T) {
+	stored := newPurchaseOrder()
+	stored.ID = 1
+	stored.OrderStatusID = OrderStatusPicking
+	repo := new(dbPOMock)
+	repo.On("Get", mock.Anything, 1).Return(stored, nil)
+	repo.On("ExistsOrderStatus", mock.Anything, OrderStatusCancelled).Return(true)
+	repo.On("UpdateStatus", mock.Anything, 1, OrderStatusPicking, OrderStatusCancelled).Return(ErrInvalidStatusTransition)
+	s := NewService(repo)
+	_, err := s.UpdateStatus(context.Background(), 1, OrderStatusCancelled)
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+}
@@ -5,15 +5,21 @@ import (
 	"database/sql"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/stockMovements"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/store"
 )
 
 // Repository encapsulates the storage of a section.
 type Repository interface {
 	GetAll(ctx context.Context) ([]domain.Section, error)
 	Get(ctx context.Context, id int) (domain.Section, error)
-	Exists(ctx context.Context, cid int) bool
+	Exists(ctx context.Context, id, warehouseId, sectionNumber int) bool
+	ExistsProductType(ctx context.Context, productTypeId int) bool
+	ExistsWarehouse(ctx context.Context, warehouseId int) bool
+	ExistsEmployee(ctx context.Context, employeeId int) bool
+	GetProductTemperatureRanges(ctx context.Context, sectionId int) ([]domain.ProductTemperatureRange, error)
 	Save(ctx context.Context, s domain.Section) (int, error)
-	Update(ctx context.Context, s domain.Section) error
+	Update(ctx context.Context, s domain.Section, employeeId int) error
 	Delete(ctx context.Context, id int) error
 }
 
@@ -57,13 +63,33 @@ func (r *repository) Get(ctx context.Context, id int) (domain.Section, error) {
 	return s, nil
 }
 
-func (r *repository) Exists(ctx context.Context, sectionNumber int) bool {
-	query := "SELECT section_number FROM sections WHERE section_number=?;"
-	row := r.db.QueryRow(query, sectionNumber)
+// Exists reports whether another section than id (0 when creating) already uses
+// sectionNumber in the warehouse.
+func (r *repository) Exists(ctx context.Context, id, warehouseId, sectionNumber int) bool {
+	query := "SELECT section_number FROM sections WHERE warehouse_id=? AND section_number=? AND id<>?;"
+	row := r.db.QueryRow(query, warehouseId, sectionNumber, id)
 	err := row.Scan(&sectionNumber)
 	return err == nil
 }
 
+func (r *repository) ExistsProductType(ctx context.Context, productTypeId int) bool {
+	query := "SELECT id FROM product_types WHERE id=?;"
+	row := r.db.QueryRow(query, productTypeId)
+	err := row.Scan(&productTypeId)
+	return err == nil
+}
+
+func (r *repository) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	return store.ExistsWarehouse(ctx, r.db, warehouseId)
+}
+
+func (r *repository) ExistsEmployee(ctx context.Context, employeeId int) bool {
+	query := "SELECT id FROM employees WHERE id=?;"
+	row := r.db.QueryRow(query, employeeId)
+	err := row.Scan(&employeeId)
+	return err == nil
+}
+
 func (r *repository) Save(ctx context.Context, s domain.Section) (int, error) {
 	query := "INSERT INTO sections (section_number, current_temperature, minimum_temperature, current_capacity, minimum_capacity, maximum_capacity, warehouse_id, id_product_type) VALUES (?, ?, ?, ?, ?, ?, ?, ?);"
 	stmt, err := r.db.Prepare(query)
@@ -73,6 +99,9 @@ func (r *repository) Save(ctx context.Context, s domain.Section) (int, error) {
 
 	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
 	if err != nil {
+		if store.IsDuplicateEntry(err) {
+			return 0, ErrAlreadyExists
+		}
 		return 0, err
 	}
 
@@ -84,46 +113,144 @@ func (r *repository) Save(ctx context.Context, s domain.Section) (int, error) {
 	return int(id), nil
 }
 
-func (r *repository) Update(ctx context.Context, s domain.Section) error {
-	query := "UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, current_capacity=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=? WHERE id=?;"
-	stmt, err := r.db.Prepare(query)
+// Update replaces the section and, when current_capacity changes, records the difference
+// as an adjustment movement of employeeId in the same transaction.
+func (r *repository) Update(ctx context.Context, s domain.Section, employeeId int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	res, err := stmt.Exec(&s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.ID)
+	var previousCapacity int
+	query := "SELECT current_capacity FROM sections WHERE id=? FOR UPDATE;"
+	if err := tx.QueryRowContext(ctx, query, s.ID).Scan(&previousCapacity); err != nil {
+		return err
+	}
+	delta := s.CurrentCapacity - previousCapacity
+	if delta != 0 && employeeId == 0 {
+		return ErrEmployeeRequired
+	}
+
+	query = "UPDATE sections SET section_number=?, current_temperature=?, minimum_temperature=?, current_capacity=?, minimum_capacity=?, maximum_capacity=?, warehouse_id=?, id_product_type=? WHERE id=?;"
+	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
+	defer stmt.Close()
 
-	_, err = res.RowsAffected()
+	_, err = stmt.ExecContext(ctx, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID, &s.ID)
 	if err != nil {
+		if store.IsDuplicateEntry(err) {
+			return ErrAlreadyExists
+		}
 		return err
 	}
 
-	return nil
+	if delta != 0 {
+		err = stockMovements.Record(ctx, tx, domain.StockMovement{
+			Type:       stockMovements.TypeAdjustment,
+			SectionID:  s.ID,
+			Quantity:   delta,
+			EmployeeID: employeeId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
+// Delete removes the section after writing off the stock it still holds: a write-off
+// movement per batch with quantity, which is left at zero, plus one for the capacity not
+// held by any batch, all in the same transaction.
 func (r *repository) Delete(ctx context.Context, id int) error {
-	query := "DELETE FROM sections WHERE id=?;"
-	stmt, err := r.db.Prepare(query)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	res, err := stmt.Exec(id)
-	if err != nil {
+	var capacity int
+	query := "SELECT current_capacity FROM sections WHERE id=? FOR UPDATE;"
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&capacity); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
 		return err
 	}
 
-	affect, err := res.RowsAffected()
+	query = "SELECT id, current_quantity FROM product_batches WHERE section_id=? AND current_quantity > 0 FOR UPDATE;"
+	rows, err := tx.QueryContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
+	var batches []domain.ProductBatches
+	for rows.Next() {
+		b := domain.ProductBatches{}
+		if err := rows.Scan(&b.ID, &b.CurrentQuantity); err != nil {
+			rows.Close()
+			return err
+		}
+		batches = append(batches, b)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	for _, b := range batches {
+		query = "UPDATE product_batches SET current_quantity=0 WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, b.ID); err != nil {
+			return err
+		}
+		err = stockMovements.Record(ctx, tx, domain.StockMovement{
+			Type:           stockMovements.TypeWriteOff,
+			ProductBatchID: b.ID,
+			SectionID:      id,
+			Quantity:       -b.CurrentQuantity,
+		})
+		if err != nil {
+			return err
+		}
+		capacity -= b.CurrentQuantity
+	}
+	if capacity != 0 {
+		err = stockMovements.Record(ctx, tx, domain.StockMovement{
+			Type:      stockMovements.TypeWriteOff,
+			SectionID: id,
+			Quantity:  -capacity,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	query = "DELETE FROM sections WHERE id=?;"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
 
-	if affect < 1 {
-		return ErrNotFound
+	return tx.Commit()
+}
+
+func (r *repository) GetProductTemperatureRanges(ctx context.Context, sectionId int) ([]domain.ProductTemperatureRange, error) {
+	query := "SELECT pb.batch_number, pb.product_id, pb.minimum_temperature, p.recommended_freezing_temperature FROM product_batches pb INNER JOIN products p ON p.id = pb.product_id WHERE pb.section_id=? AND pb.current_quantity > 0;"
+	rows, err := r.db.Query(query, sectionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranges []domain.ProductTemperatureRange
+
+	for rows.Next() {
+		tr := domain.ProductTemperatureRange{}
+		if err := rows.Scan(&tr.BatchNumber, &tr.ProductID, &tr.MinimumTemperature, &tr.RecomFreezTemp); err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, tr)
 	}
 
-	return nil
+	return ranges, nil
 }
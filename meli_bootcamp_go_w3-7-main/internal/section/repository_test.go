@@ -0,0 +1,64 @@
+package section
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteWritesOffStock(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT current_capacity FROM sections WHERE id=\\? FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_capacity"}).AddRow(50))
+	mock.ExpectQuery("SELECT id, current_quantity FROM product_batches WHERE section_id=\\? AND current_quantity > 0 FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "current_quantity"}).AddRow(7, 30))
+	mock.ExpectExec("UPDATE product_batches SET current_quantity=0").WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO stock_movements").
+		WithArgs("write-off", 7, 1, -30, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO stock_movements").
+		WithArgs("write-off", nil, 1, -20, nil, nil).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec("DELETE FROM sections WHERE id=\\?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := NewRepository(db).Delete(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteEmptySectionWritesNothing(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT current_capacity FROM sections").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_capacity"}).AddRow(0))
+	mock.ExpectQuery("FROM product_batches").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "current_quantity"}))
+	mock.ExpectExec("DELETE FROM sections WHERE id=\\?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err := NewRepository(db).Delete(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteNotFound(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT current_capacity FROM sections").
+		WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"current_capacity"}))
+	mock.ExpectRollback()
+	err := NewRepository(db).Delete(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
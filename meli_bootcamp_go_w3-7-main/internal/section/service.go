@@ -3,23 +3,29 @@ package section
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 )
 
 // Errors
 var (
-	ErrNotFound      = errors.New("la seccion no fue encontrada")
-	ErrAlreadyExists = errors.New("la seccion ya existe")
+	ErrNotFound              = errors.New("la seccion no fue encontrada")
+	ErrAlreadyExists         = errors.New("la seccion ya existe")
+	ErrProductTypeNotFound   = errors.New("el product type de la seccion no existe")
+	ErrWarehouseNotFound     = errors.New("el warehouse de la seccion no existe")
+	ErrTemperatureOutOfRange = errors.New("la temperatura de la seccion esta fuera del rango recomendado de sus productos")
+	ErrEmployeeRequired      = errors.New("el employee_id es requerido para modificar current_capacity")
+	ErrEmployeeNotFound      = errors.New("el employee no existe")
 )
 
 type Service interface {
 	GetAll(ctx context.Context) ([]domain.Section, error)
 	Get(ctx context.Context, id int) (domain.Section, error)
 	Save(ctx context.Context, sec domain.Section) (domain.Section, error)
-	Update(ctx context.Context, sec domain.Section) (domain.Section, error)
+	Update(ctx context.Context, sec domain.Section, employeeId int) (domain.Section, error)
 	Delete(ctx context.Context, id int) error
-	Exists(ctx context.Context, sectionNumber int) bool
+	Exists(ctx context.Context, warehouseId, sectionNumber int) bool
 }
 
 type service struct {
@@ -50,10 +56,16 @@ func (s *service) Get(ctx context.Context, id int) (domain.Section, error) {
 }
 
 func (s *service) Save(ctx context.Context, sect domain.Section) (domain.Section, error) {
-	exist := s.sectionRepository.Exists(ctx, sect.SectionNumber)
+	exist := s.sectionRepository.Exists(ctx, 0, sect.WarehouseID, sect.SectionNumber)
 	if exist {
 		return domain.Section{}, ErrAlreadyExists
 	}
+	if !s.sectionRepository.ExistsProductType(ctx, sect.ProductTypeID) {
+		return domain.Section{}, ErrProductTypeNotFound
+	}
+	if !s.sectionRepository.ExistsWarehouse(ctx, sect.WarehouseID) {
+		return domain.Section{}, ErrWarehouseNotFound
+	}
 	newSectionId, err := s.sectionRepository.Save(ctx, sect)
 	if err != nil {
 		return domain.Section{}, err
@@ -62,9 +74,33 @@ func (s *service) Save(ctx context.Context, sect domain.Section) (domain.Section
 	return sect, nil
 }
 
-func (s *service) Update(ctx context.Context, sect domain.Section) (domain.Section, error) {
-	err := s.sectionRepository.Update(ctx, sect)
+// Update replaces the section. employeeId is recorded as the author of the capacity
+// adjustment and is required when current_capacity changes.
+func (s *service) Update(ctx context.Context, sect domain.Section, employeeId int) (domain.Section, error) {
+	ranges, err := s.sectionRepository.GetProductTemperatureRanges(ctx, sect.ID)
 	if err != nil {
+		return domain.Section{}, err
+	}
+	if err := checkTemperature(sect, ranges); err != nil {
+		return domain.Section{}, err
+	}
+	if !s.sectionRepository.ExistsProductType(ctx, sect.ProductTypeID) {
+		return domain.Section{}, ErrProductTypeNotFound
+	}
+	if !s.sectionRepository.ExistsWarehouse(ctx, sect.WarehouseID) {
+		return domain.Section{}, ErrWarehouseNotFound
+	}
+	if s.sectionRepository.Exists(ctx, sect.ID, sect.WarehouseID, sect.SectionNumber) {
+		return domain.Section{}, ErrAlreadyExists
+	}
+	if employeeId != 0 && !s.sectionRepository.ExistsEmployee(ctx, employeeId) {
+		return domain.Section{}, ErrEmployeeNotFound
+	}
+	err = s.sectionRepository.Update(ctx, sect, employeeId)
+	if err != nil {
+		if errors.Is(err, ErrAlreadyExists) || errors.Is(err, ErrEmployeeRequired) {
+			return domain.Section{}, err
+		}
 		return domain.Section{}, ErrNotFound
 	}
 	return sect, nil
@@ -75,6 +111,23 @@ func (s *service) Delete(ctx context.Context, id int) error {
 	return s.sectionRepository.Delete(ctx, id)
 }
 
-func (s *service) Exists(ctx context.Context, sec int) bool {
-	return s.sectionRepository.Exists(ctx, sec)
+func (s *service) Exists(ctx context.Context, warehouseId, sectionNumber int) bool {
+	return s.sectionRepository.Exists(ctx, 0, warehouseId, sectionNumber)
+}
+
+// checkTemperature verifies that the section temperature stays inside the range of every
+// stored batch: between the warmer of the batch's minimum_temperature and the section's
+// own minimum_temperature, and the product's recommended freezing temperature.
+func checkTemperature(sect domain.Section, ranges []domain.ProductTemperatureRange) error {
+	temperature := float32(sect.CurrentTemperature)
+	for _, tr := range ranges {
+		minTemperature := tr.MinimumTemperature
+		if float32(sect.MinimumTemperature) > minTemperature {
+			minTemperature = float32(sect.MinimumTemperature)
+		}
+		if temperature < minTemperature || temperature > tr.RecomFreezTemp {
+			return fmt.Errorf("%w: la seccion %d estaria a %d grados y el producto %d del batch %d requiere entre %.2f y %.2f grados", ErrTemperatureOutOfRange, sect.ID, sect.CurrentTemperature, tr.ProductID, tr.BatchNumber, minTemperature, tr.RecomFreezTemp)
+		}
+	}
+	return nil
 }
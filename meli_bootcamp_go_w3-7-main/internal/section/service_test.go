@@ -22,16 +22,32 @@ func (r *repoM) Get(ctx context.Context, id int) (domain.Section, error) {
 	args := r.Called(ctx, id)
 	return args.Get(0).(domain.Section), args.Error(1)
 }
-func (r *repoM) Exists(ctx context.Context, sectionNumber int) bool {
-	args := r.Called(ctx, sectionNumber)
+func (r *repoM) Exists(ctx context.Context, id, warehouseId, sectionNumber int) bool {
+	args := r.Called(ctx, id, warehouseId, sectionNumber)
 	return args.Bool(0)
 }
+func (r *repoM) ExistsProductType(ctx context.Context, productTypeId int) bool {
+	args := r.Called(ctx, productTypeId)
+	return args.Bool(0)
+}
+func (r *repoM) ExistsWarehouse(ctx context.Context, warehouseId int) bool {
+	args := r.Called(ctx, warehouseId)
+	return args.Bool(0)
+}
+func (r *repoM) GetProductTemperatureRanges(ctx context.Context, sectionId int) ([]domain.ProductTemperatureRange, error) {
+	args := r.Called(ctx, sectionId)
+	return args.Get(0).([]domain.ProductTemperatureRange), args.Error(1)
+}
 func (r *repoM) Save(ctx context.Context, se domain.Section) (int, error) {
 	args := r.Called(ctx, se)
 	return args.Int(0), args.Error(1)
 }
-func (r *repoM) Update(ctx context.Context, se domain.Section) error {
-	args := r.Called(ctx, se)
+func (r *repoM) ExistsEmployee(ctx context.Context, employeeId int) bool {
+	args := r.Called(ctx, employeeId)
+	return args.Bool(0)
+}
+func (r *repoM) Update(ctx context.Context, se domain.Section, employeeId int) error {
+	args := r.Called(ctx, se, employeeId)
 	return args.Error(0)
 }
 func (r *repoM) Delete(ctx context.Context, id int) error {
@@ -42,7 +58,9 @@ func (r *repoM) Delete(ctx context.Context, id int) error {
 func TestCreateOk(t *testing.T) {
 	repo := new(repoM)
 	repo.On("Save", mock.Anything, mock.Anything).Return(1, nil)
-	repo.On("Exists", mock.Anything, mock.Anything).Return(false)
+	repo.On("Exists", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false)
+	repo.On("ExistsProductType", mock.Anything, 3).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 3).Return(true)
 	s := NewService(repo)
 	objetoAPersistir := domain.Section{
 		SectionNumber:      41,
@@ -64,7 +82,7 @@ func TestCreateConflict(t *testing.T) {
 	expectedError := errors.New("la seccion ya existe")
 	expectedResult := domain.Section{}
 	repo := new(repoM)
-	repo.On("Exists", mock.Anything, mock.Anything).Return(true)
+	repo.On("Exists", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true)
 	serviceT := NewService(repo)
 	ctx := context.Background()
 	resul, err := serviceT.Save(ctx, expectedResult)
@@ -72,6 +90,74 @@ func TestCreateConflict(t *testing.T) {
 	assert.Equal(t, expectedResult, resul)
 }
 
+func TestCreateProductTypeNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false)
+	repo.On("ExistsProductType", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	resul, err := s.Save(context.Background(), domain.Section{SectionNumber: 41, ProductTypeID: 9})
+	assert.ErrorIs(t, err, ErrProductTypeNotFound)
+	assert.Equal(t, domain.Section{}, resul)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCreateWarehouseNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false)
+	repo.On("ExistsProductType", mock.Anything, 3).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	resul, err := s.Save(context.Background(), domain.Section{SectionNumber: 41, ProductTypeID: 3, WarehouseID: 9})
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+	assert.Equal(t, domain.Section{}, resul)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestUpdateWarehouseNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 1).Return([]domain.ProductTemperatureRange{}, nil)
+	repo.On("ExistsProductType", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Section{ID: 1, SectionNumber: 41, WarehouseID: 9}, 0)
+	assert.ErrorIs(t, err, ErrWarehouseNotFound)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateProductTypeNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 1).Return([]domain.ProductTemperatureRange{}, nil)
+	repo.On("ExistsProductType", mock.Anything, 99).Return(false)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Section{ID: 1, SectionNumber: 41, WarehouseID: 4, ProductTypeID: 99}, 0)
+	assert.ErrorIs(t, err, ErrProductTypeNotFound)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateSameNumberOtherWarehouse(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Exists", mock.Anything, 0, 4, 1).Return(false)
+	repo.On("ExistsProductType", mock.Anything, 3).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 4).Return(true)
+	repo.On("Save", mock.Anything, mock.Anything).Return(2, nil)
+	s := NewService(repo)
+	ns, err := s.Save(context.Background(), domain.Section{SectionNumber: 1, ProductTypeID: 3, WarehouseID: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ns.ID)
+}
+
+func TestUpdateDuplicatedSectionNumber(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 1).Return([]domain.ProductTemperatureRange{}, nil)
+	repo.On("ExistsProductType", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 4).Return(true)
+	repo.On("Exists", mock.Anything, 1, 4, 7).Return(true)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Section{ID: 1, SectionNumber: 7, WarehouseID: 4}, 0)
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestFindAll(t *testing.T) {
 	repo := new(repoM)
 	repo.On("GetAll", mock.Anything).Return([]domain.Section{
@@ -137,23 +223,32 @@ func TestUpdateOk(t *testing.T) {
 	objetoAc := domain.Section{
 		SectionNumber:      41,
 		CurrentTemperature: 3,
-		MinimumTemperature: 4,
+		MinimumTemperature: 2,
 		CurrentCapacity:    41,
 		MinimumCapacity:    41,
 		MaximumCapacity:    43,
 		WarehouseID:        43,
 		ProductTypeID:      43,
 	}
-	repo.On("Update", mock.Anything, objetoAc).Return(nil)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 0).Return([]domain.ProductTemperatureRange{{BatchNumber: 1, ProductID: 1, MinimumTemperature: 1, RecomFreezTemp: 5}}, nil)
+	repo.On("ExistsProductType", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 43).Return(true)
+	repo.On("Exists", mock.Anything, 0, 43, 41).Return(false)
+	repo.On("ExistsEmployee", mock.Anything, 5).Return(true)
+	repo.On("Update", mock.Anything, objetoAc, 5).Return(nil)
 	s := NewService(repo)
-	objetoRecuperado, err := s.Update(context.Background(), objetoAc)
+	objetoRecuperado, err := s.Update(context.Background(), objetoAc, 5)
 	assert.NoError(t, err)
 	assert.Equal(t, objetoAc, objetoRecuperado, "Los sections no coinciden")
 }
 
 func TestUpdateFail(t *testing.T) {
 	repo := new(repoM)
-	repo.On("Update", mock.Anything, mock.Anything).Return(errors.New("No se puede modificar el section"))
+	repo.On("GetProductTemperatureRanges", mock.Anything, mock.Anything).Return([]domain.ProductTemperatureRange{}, nil)
+	repo.On("ExistsProductType", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, mock.Anything).Return(true)
+	repo.On("Exists", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false)
+	repo.On("Update", mock.Anything, mock.Anything, 0).Return(errors.New("No se puede modificar el section"))
 	s := NewService(repo)
 	objetoAc := domain.Section{
 		SectionNumber:      41,
@@ -165,11 +260,56 @@ func TestUpdateFail(t *testing.T) {
 		WarehouseID:        43,
 		ProductTypeID:      43,
 	}
-	objetoRecuperado, err := s.Update(context.Background(), objetoAc)
+	objetoRecuperado, err := s.Update(context.Background(), objetoAc, 0)
 	assert.Error(t, err)
 	assert.Equal(t, domain.Section{}, objetoRecuperado, "Los section no coinciden")
 }
 
+func TestUpdateTemperatureOutOfRange(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 1).Return([]domain.ProductTemperatureRange{{BatchNumber: 7, ProductID: 2, MinimumTemperature: -25, RecomFreezTemp: -18}}, nil)
+	s := NewService(repo)
+	objetoRecuperado, err := s.Update(context.Background(), domain.Section{ID: 1, SectionNumber: 41, CurrentTemperature: 3, MinimumTemperature: -30}, 0)
+	assert.ErrorIs(t, err, ErrTemperatureOutOfRange)
+	assert.Contains(t, err.Error(), "producto 2 del batch 7 requiere entre -25.00 y -18.00 grados")
+	assert.Equal(t, domain.Section{}, objetoRecuperado)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateTemperatureBelowSectionMinimum(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 1).Return([]domain.ProductTemperatureRange{{BatchNumber: 7, ProductID: 2, MinimumTemperature: -25, RecomFreezTemp: -18}}, nil)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Section{ID: 1, SectionNumber: 41, CurrentTemperature: -24, MinimumTemperature: -22}, 0)
+	assert.ErrorIs(t, err, ErrTemperatureOutOfRange)
+	assert.Contains(t, err.Error(), "entre -22.00 y -18.00 grados")
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+func TestUpdateEmployeeNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 1).Return([]domain.ProductTemperatureRange{}, nil)
+	repo.On("ExistsProductType", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 4).Return(true)
+	repo.On("Exists", mock.Anything, 1, 4, 7).Return(false)
+	repo.On("ExistsEmployee", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Section{ID: 1, SectionNumber: 7, WarehouseID: 4, CurrentCapacity: 10}, 9)
+	assert.ErrorIs(t, err, ErrEmployeeNotFound)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateCapacityWithoutEmployee(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetProductTemperatureRanges", mock.Anything, 1).Return([]domain.ProductTemperatureRange{}, nil)
+	repo.On("ExistsProductType", mock.Anything, mock.Anything).Return(true)
+	repo.On("ExistsWarehouse", mock.Anything, 4).Return(true)
+	repo.On("Exists", mock.Anything, 1, 4, 7).Return(false)
+	repo.On("Update", mock.Anything, mock.Anything, 0).Return(ErrEmployeeRequired)
+	s := NewService(repo)
+	_, err := s.Update(context.Background(), domain.Section{ID: 1, SectionNumber: 7, WarehouseID: 4, CurrentCapacity: 10}, 0)
+	assert.ErrorIs(t, err, ErrEmployeeRequired)
+}
+
 func TestDeleteOk(t *testing.T) {
 	repo := new(repoM)
 	repo.On("Delete", mock.Anything, mock.Anything).Return(nil)
@@ -0,0 +1,242 @@
+package shipments
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/purchaseOrders"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/pkg/store"
+)
+
+// Repository encapsulates the storage of the shipments and their tracking events.
+type Repository interface {
+	Get(ctx context.Context, trackingCode string) (domain.Shipment, error)
+	Save(ctx context.Context, s domain.Shipment) (int, error)
+	AddEvent(ctx context.Context, trackingCode string, e domain.ShipmentEvent) (int, error)
+	ExistsCarry(ctx context.Context, carryId int) bool
+	ExistsProductBatch(ctx context.Context, productBatchId int) bool
+	ExistsForPurchaseOrder(ctx context.Context, purchaseOrderId int) bool
+	GetPurchaseOrderStatus(ctx context.Context, purchaseOrderId int) (int, error)
+}
+
+const (
+	StatusAssigned   = "assigned"
+	StatusDispatched = "dispatched"
+	StatusInTransit  = "in-transit"
+	StatusDelivered  = "delivered"
+
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// purchaseOrderStatuses maps the events that move a linked purchase order forward
+// to the status it moves from and the one it moves to.
+var purchaseOrderStatuses = map[string][2]int{
+	StatusDispatched: {purchaseOrders.OrderStatusPicking, purchaseOrders.OrderStatusShipped},
+	StatusDelivered:  {purchaseOrders.OrderStatusShipped, purchaseOrders.OrderStatusDelivered},
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Get(ctx context.Context, trackingCode string) (domain.Shipment, error) {
+	query := "SELECT id, tracking_code, carry_id, purchase_order_id, status, created_at FROM shipments WHERE tracking_code=?;"
+	s := domain.Shipment{}
+	var purchaseOrderId sql.NullInt64
+	var createdAt string
+	err := r.db.QueryRowContext(ctx, query, trackingCode).Scan(&s.ID, &s.TrackingCode, &s.CarryID, &purchaseOrderId, &s.Status, &createdAt)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	s.PurchaseOrderID = int(purchaseOrderId.Int64)
+	if s.CreatedAt, err = time.Parse(dateTimeLayout, createdAt); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	query = "SELECT product_batch_id FROM shipment_batches WHERE shipment_id=? ORDER BY product_batch_id;"
+	rows, err := r.db.QueryContext(ctx, query, s.ID)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var productBatchId int
+		if err := rows.Scan(&productBatchId); err != nil {
+			return domain.Shipment{}, err
+		}
+		s.ProductBatchIDs = append(s.ProductBatchIDs, productBatchId)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	query = "SELECT id, event_type, location, occurred_at FROM shipment_events WHERE shipment_id=? ORDER BY occurred_at, id;"
+	eventRows, err := r.db.QueryContext(ctx, query, s.ID)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	defer eventRows.Close()
+	s.Events = []domain.ShipmentEvent{}
+	for eventRows.Next() {
+		e := domain.ShipmentEvent{}
+		var location sql.NullString
+		var occurredAt string
+		if err := eventRows.Scan(&e.ID, &e.Type, &location, &occurredAt); err != nil {
+			return domain.Shipment{}, err
+		}
+		e.Location = location.String
+		if e.OccurredAt, err = time.Parse(dateTimeLayout, occurredAt); err != nil {
+			return domain.Shipment{}, err
+		}
+		s.Events = append(s.Events, e)
+	}
+
+	return s, eventRows.Err()
+}
+
+// Save stores the shipment with its batches and, for a purchase order, copies the
+// tracking code onto the order in the same transaction. A second shipment for the same
+// purchase order is rejected by the shipments_purchase_order_id_uindex unique index.
+func (r *repository) Save(ctx context.Context, s domain.Shipment) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO shipments (tracking_code, carry_id, purchase_order_id, status, created_at) VALUES (?, ?, ?, ?, UTC_TIMESTAMP());"
+	res, err := tx.ExecContext(ctx, query, s.TrackingCode, s.CarryID, sql.NullInt64{Int64: int64(s.PurchaseOrderID), Valid: s.PurchaseOrderID != 0}, StatusAssigned)
+	if err != nil {
+		if store.IsDuplicateKey(err, "shipments_purchase_order_id_uindex") {
+			return 0, ErrAlreadyExists
+		}
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(s.ProductBatchIDs) > 0 {
+		stmt, err := tx.PrepareContext(ctx, "INSERT INTO shipment_batches (shipment_id, product_batch_id) VALUES (?, ?);")
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Close()
+		for _, productBatchId := range s.ProductBatchIDs {
+			if _, err := stmt.ExecContext(ctx, id, productBatchId); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if s.PurchaseOrderID != 0 {
+		query = "UPDATE purchase_orders SET tracking_code=? WHERE id=?;"
+		if _, err := tx.ExecContext(ctx, query, s.TrackingCode, s.PurchaseOrderID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// AddEvent appends e to the shipment after checking the status transition, and moves
+// the linked purchase order to shipped or delivered, all in a single transaction.
+func (r *repository) AddEvent(ctx context.Context, trackingCode string, e domain.ShipmentEvent) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var shipmentId int
+	var status string
+	var purchaseOrderId sql.NullInt64
+	query := "SELECT id, status, purchase_order_id FROM shipments WHERE tracking_code=? FOR UPDATE;"
+	if err := tx.QueryRowContext(ctx, query, trackingCode).Scan(&shipmentId, &status, &purchaseOrderId); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	if !canTransition(status, e.Type) {
+		return 0, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, status, e.Type)
+	}
+
+	query = "INSERT INTO shipment_events (shipment_id, event_type, location, occurred_at) VALUES (?, ?, ?, UTC_TIMESTAMP());"
+	res, err := tx.ExecContext(ctx, query, shipmentId, e.Type, sql.NullString{String: e.Location, Valid: e.Location != ""})
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	query = "UPDATE shipments SET status=? WHERE id=?;"
+	if _, err := tx.ExecContext(ctx, query, e.Type, shipmentId); err != nil {
+		return 0, err
+	}
+
+	if change, ok := purchaseOrderStatuses[e.Type]; ok && purchaseOrderId.Valid {
+		query = "UPDATE purchase_orders SET order_status_id=? WHERE id=? AND order_status_id=?;"
+		res, err := tx.ExecContext(ctx, query, change[1], purchaseOrderId.Int64, change[0])
+		if err != nil {
+			return 0, err
+		}
+		affect, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if affect < 1 {
+			return 0, fmt.Errorf("%w: the purchase order %d is no longer in status %d", ErrPurchaseOrderNotReady, purchaseOrderId.Int64, change[0])
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func (r *repository) ExistsCarry(ctx context.Context, carryId int) bool {
+	query := "SELECT id FROM carries WHERE id=?;"
+	row := r.db.QueryRow(query, carryId)
+	err := row.Scan(&carryId)
+	return err == nil
+}
+
+func (r *repository) ExistsProductBatch(ctx context.Context, productBatchId int) bool {
+	query := "SELECT id FROM product_batches WHERE id=?;"
+	row := r.db.QueryRow(query, productBatchId)
+	err := row.Scan(&productBatchId)
+	return err == nil
+}
+
+func (r *repository) ExistsForPurchaseOrder(ctx context.Context, purchaseOrderId int) bool {
+	query := "SELECT purchase_order_id FROM shipments WHERE purchase_order_id=?;"
+	row := r.db.QueryRow(query, purchaseOrderId)
+	err := row.Scan(&purchaseOrderId)
+	return err == nil
+}
+
+func (r *repository) GetPurchaseOrderStatus(ctx context.Context, purchaseOrderId int) (int, error) {
+	var orderStatusId int
+	query := "SELECT order_status_id FROM purchase_orders WHERE id=?;"
+	err := r.db.QueryRowContext(ctx, query, purchaseOrderId).Scan(&orderStatusId)
+	return orderStatusId, err
+}
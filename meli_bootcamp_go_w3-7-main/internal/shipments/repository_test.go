@@ -0,0 +1,103 @@
+package shipments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/purchaseOrders"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddEventDispatchShipsPurchaseOrder(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, status, purchase_order_id FROM shipments WHERE tracking_code=\\? FOR UPDATE").
+		WithArgs("TRABC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "purchase_order_id"}).AddRow(3, StatusAssigned, 7))
+	mock.ExpectExec("INSERT INTO shipment_events").
+		WithArgs(3, StatusDispatched, "Buenos Aires").
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectExec("UPDATE shipments SET status=\\?").
+		WithArgs(StatusDispatched, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE purchase_orders SET order_status_id=\\? WHERE id=\\? AND order_status_id=\\?").
+		WithArgs(purchaseOrders.OrderStatusShipped, 7, purchaseOrders.OrderStatusPicking).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	r := NewRepository(db)
+	id, err := r.AddEvent(context.Background(), "TRABC", domain.ShipmentEvent{Type: StatusDispatched, Location: "Buenos Aires"})
+	assert.NoError(t, err)
+	assert.Equal(t, 11, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddEventInvalidTransition(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM shipments WHERE tracking_code=\\? FOR UPDATE").
+		WithArgs("TRABC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "purchase_order_id"}).AddRow(3, StatusDelivered, nil))
+	mock.ExpectRollback()
+	r := NewRepository(db)
+	_, err := r.AddEvent(context.Background(), "TRABC", domain.ShipmentEvent{Type: StatusInTransit})
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddEventPurchaseOrderCancelled(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM shipments WHERE tracking_code=\\? FOR UPDATE").
+		WithArgs("TRABC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "purchase_order_id"}).AddRow(3, StatusAssigned, 7))
+	mock.ExpectExec("INSERT INTO shipment_events").WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectExec("UPDATE shipments SET status=\\?").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE purchase_orders").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	r := NewRepository(db)
+	_, err := r.AddEvent(context.Background(), "TRABC", domain.ShipmentEvent{Type: StatusDispatched})
+	assert.ErrorIs(t, err, ErrPurchaseOrderNotReady)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveSecondShipmentForPurchaseOrder(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO shipments").
+		WithArgs("TRABC", 1, 7, StatusAssigned).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '7' for key 'shipments_purchase_order_id_uindex'"})
+	mock.ExpectRollback()
+
+	repo := NewRepository(db)
+	_, err := repo.Save(context.Background(), domain.Shipment{TrackingCode: "TRABC", CarryID: 1, PurchaseOrderID: 7})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveTrackingCodeCollision(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO shipments").
+		WithArgs("TRABC", 1, 7, StatusAssigned).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'TRABC' for key 'shipments_tracking_code_uindex'"})
+	mock.ExpectRollback()
+
+	repo := NewRepository(db)
+	_, err := repo.Save(context.Background(), domain.Shipment{TrackingCode: "TRABC", CarryID: 1, PurchaseOrderID: 7})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,166 @@
+package shipments
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/purchaseOrders"
+)
+
+// Errors
+var (
+	ErrNotFound              = errors.New("shipment not found")
+	ErrAlreadyExists         = errors.New("the purchase order already has a shipment")
+	ErrCarryNotFound         = errors.New("the carry doesn't exist")
+	ErrPurchaseOrderNotFound = errors.New("the purchase order doesn't exist")
+	ErrPurchaseOrderNotReady = errors.New("the purchase order is not ready to be shipped")
+	ErrProductBatchNotFound  = errors.New("the product batch doesn't exist")
+	ErrInvalidContent        = errors.New("a shipment needs either a purchase order or a list of product batches")
+	ErrInvalidEventType      = errors.New("the event type must be dispatched, in-transit or delivered")
+	ErrInvalidTransition     = errors.New("invalid shipment status transition")
+)
+
+// statusTransitions lists, for every status, the events a shipment accepts.
+// In-transit can be repeated for every checkpoint and delivered is final.
+var statusTransitions = map[string][]string{
+	StatusAssigned:   {StatusDispatched},
+	StatusDispatched: {StatusInTransit, StatusDelivered},
+	StatusInTransit:  {StatusInTransit, StatusDelivered},
+}
+
+const (
+	trackingCodePrefix   = "TR"
+	trackingCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	trackingCodeLength   = 10
+)
+
+type Service interface {
+	Get(ctx context.Context, trackingCode string) (domain.Shipment, error)
+	Save(ctx context.Context, s domain.Shipment) (domain.Shipment, error)
+	AddEvent(ctx context.Context, trackingCode string, e domain.ShipmentEvent) (domain.ShipmentEvent, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) Get(ctx context.Context, trackingCode string) (domain.Shipment, error) {
+	shipment, err := s.repository.Get(ctx, trackingCode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Shipment{}, ErrNotFound
+		}
+		return domain.Shipment{}, err
+	}
+	return shipment, nil
+}
+
+func (s *service) Save(ctx context.Context, shipment domain.Shipment) (domain.Shipment, error) {
+	if (shipment.PurchaseOrderID == 0) == (len(shipment.ProductBatchIDs) == 0) {
+		return domain.Shipment{}, ErrInvalidContent
+	}
+	if !s.repository.ExistsCarry(ctx, shipment.CarryID) {
+		return domain.Shipment{}, ErrCarryNotFound
+	}
+	if shipment.PurchaseOrderID != 0 {
+		if err := s.checkPurchaseOrder(ctx, shipment.PurchaseOrderID); err != nil {
+			return domain.Shipment{}, err
+		}
+	}
+	shipment.ProductBatchIDs = uniqueIDs(shipment.ProductBatchIDs)
+	for _, productBatchId := range shipment.ProductBatchIDs {
+		if !s.repository.ExistsProductBatch(ctx, productBatchId) {
+			return domain.Shipment{}, fmt.Errorf("%w: %d", ErrProductBatchNotFound, productBatchId)
+		}
+	}
+	trackingCode, err := newTrackingCode()
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	shipment.TrackingCode = trackingCode
+	shipment.Status = StatusAssigned
+	id, err := s.repository.Save(ctx, shipment)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	shipment.ID = id
+	shipment.Events = []domain.ShipmentEvent{}
+	return shipment, nil
+}
+
+func (s *service) AddEvent(ctx context.Context, trackingCode string, e domain.ShipmentEvent) (domain.ShipmentEvent, error) {
+	if e.Type != StatusDispatched && e.Type != StatusInTransit && e.Type != StatusDelivered {
+		return domain.ShipmentEvent{}, ErrInvalidEventType
+	}
+	id, err := s.repository.AddEvent(ctx, trackingCode, e)
+	if err != nil {
+		return domain.ShipmentEvent{}, err
+	}
+	e.ID = id
+	return e, nil
+}
+
+// checkPurchaseOrder accepts only orders being picked that were not shipped yet.
+func (s *service) checkPurchaseOrder(ctx context.Context, purchaseOrderId int) error {
+	orderStatusId, err := s.repository.GetPurchaseOrderStatus(ctx, purchaseOrderId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPurchaseOrderNotFound
+		}
+		return err
+	}
+	if orderStatusId != purchaseOrders.OrderStatusPicking {
+		return ErrPurchaseOrderNotReady
+	}
+	if s.repository.ExistsForPurchaseOrder(ctx, purchaseOrderId) {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+func canTransition(from string, to string) bool {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := map[int]bool{}
+	var unique []int
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// newTrackingCode returns a random code such as TR7KQ2M9XHAP, avoiding characters
+// that are easy to misread (0/O, 1/I).
+func newTrackingCode() (string, error) {
+	code := []byte(trackingCodePrefix)
+	max := big.NewInt(int64(len(trackingCodeAlphabet)))
+	for i := 0; i < trackingCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code = append(code, trackingCodeAlphabet[n.Int64()])
+	}
+	return string(code), nil
+}
@@ -0,0 +1,149 @@
+package shipments
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/purchaseOrders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (m *repoM) Get(ctx context.Context, trackingCode string) (domain.Shipment, error) {
+	args := m.Called(ctx, trackingCode)
+	return args.Get(0).(domain.Shipment), args.Error(1)
+}
+
+func (m *repoM) Save(ctx context.Context, s domain.Shipment) (int, error) {
+	args := m.Called(ctx, s)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *repoM) AddEvent(ctx context.Context, trackingCode string, e domain.ShipmentEvent) (int, error) {
+	args := m.Called(ctx, trackingCode, e)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *repoM) ExistsCarry(ctx context.Context, carryId int) bool {
+	args := m.Called(ctx, carryId)
+	return args.Bool(0)
+}
+
+func (m *repoM) ExistsProductBatch(ctx context.Context, productBatchId int) bool {
+	args := m.Called(ctx, productBatchId)
+	return args.Bool(0)
+}
+
+func (m *repoM) ExistsForPurchaseOrder(ctx context.Context, purchaseOrderId int) bool {
+	args := m.Called(ctx, purchaseOrderId)
+	return args.Bool(0)
+}
+
+func (m *repoM) GetPurchaseOrderStatus(ctx context.Context, purchaseOrderId int) (int, error) {
+	args := m.Called(ctx, purchaseOrderId)
+	return args.Int(0), args.Error(1)
+}
+
+func TestSavePurchaseOrderShipment(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCarry", mock.Anything, 1).Return(true)
+	repo.On("GetPurchaseOrderStatus", mock.Anything, 7).Return(purchaseOrders.OrderStatusPicking, nil)
+	repo.On("ExistsForPurchaseOrder", mock.Anything, 7).Return(false)
+	repo.On("Save", mock.Anything, mock.Anything).Return(3, nil)
+	s := NewService(repo)
+	shipment, err := s.Save(context.Background(), domain.Shipment{CarryID: 1, PurchaseOrderID: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, shipment.ID)
+	assert.Equal(t, StatusAssigned, shipment.Status)
+	assert.Regexp(t, regexp.MustCompile("^TR[A-HJ-NP-Z2-9]{10}$"), shipment.TrackingCode)
+}
+
+func TestSaveBatchesShipmentDeduplicates(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCarry", mock.Anything, 1).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, mock.Anything).Return(true)
+	repo.On("Save", mock.Anything, mock.MatchedBy(func(s domain.Shipment) bool {
+		return assert.ObjectsAreEqual([]int{4, 5}, s.ProductBatchIDs)
+	})).Return(3, nil)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Shipment{CarryID: 1, ProductBatchIDs: []int{4, 5, 4}})
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestSaveShipmentInvalidContent(t *testing.T) {
+	repo := new(repoM)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Shipment{CarryID: 1})
+	assert.ErrorIs(t, err, ErrInvalidContent)
+	_, err = s.Save(context.Background(), domain.Shipment{CarryID: 1, PurchaseOrderID: 7, ProductBatchIDs: []int{4}})
+	assert.ErrorIs(t, err, ErrInvalidContent)
+}
+
+func TestSaveShipmentCarryNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCarry", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Shipment{CarryID: 9, PurchaseOrderID: 7})
+	assert.ErrorIs(t, err, ErrCarryNotFound)
+}
+
+func TestSaveShipmentPurchaseOrderNotReady(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCarry", mock.Anything, 1).Return(true)
+	repo.On("GetPurchaseOrderStatus", mock.Anything, 7).Return(purchaseOrders.OrderStatusPending, nil)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Shipment{CarryID: 1, PurchaseOrderID: 7})
+	assert.ErrorIs(t, err, ErrPurchaseOrderNotReady)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestSaveShipmentPurchaseOrderAlreadyShipped(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCarry", mock.Anything, 1).Return(true)
+	repo.On("GetPurchaseOrderStatus", mock.Anything, 7).Return(purchaseOrders.OrderStatusPicking, nil)
+	repo.On("ExistsForPurchaseOrder", mock.Anything, 7).Return(true)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Shipment{CarryID: 1, PurchaseOrderID: 7})
+	assert.ErrorIs(t, err, ErrAlreadyExists)
+}
+
+func TestSaveShipmentProductBatchNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsCarry", mock.Anything, 1).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 4).Return(true)
+	repo.On("ExistsProductBatch", mock.Anything, 5).Return(false)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), domain.Shipment{CarryID: 1, ProductBatchIDs: []int{4, 5}})
+	assert.ErrorIs(t, err, ErrProductBatchNotFound)
+}
+
+func TestGetShipmentNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, "TRXXXX").Return(domain.Shipment{}, sql.ErrNoRows)
+	s := NewService(repo)
+	_, err := s.Get(context.Background(), "TRXXXX")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAddEventInvalidType(t *testing.T) {
+	repo := new(repoM)
+	s := NewService(repo)
+	_, err := s.AddEvent(context.Background(), "TRXXXX", domain.ShipmentEvent{Type: StatusAssigned})
+	assert.ErrorIs(t, err, ErrInvalidEventType)
+	repo.AssertNotCalled(t, "AddEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, canTransition(StatusAssigned, StatusDispatched))
+	assert.True(t, canTransition(StatusInTransit, StatusInTransit))
+	assert.False(t, canTransition(StatusAssigned, StatusDelivered))
+	assert.False(t, canTransition(StatusDelivered, StatusInTransit))
+}
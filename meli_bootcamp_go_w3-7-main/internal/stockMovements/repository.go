@@ -0,0 +1,113 @@
+package stockMovements
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Repository reads the stock movements ledger. Movements are only written through
+// Record, inside the transaction that changes the quantity.
+type Repository interface {
+	GetAll(ctx context.Context, filter domain.StockMovementFilter) ([]domain.StockMovement, int, error)
+}
+
+const (
+	TypeInbound    = "inbound"
+	TypePick       = "pick"
+	TypeAdjustment = "adjustment"
+	TypeTransfer   = "transfer"
+	TypeWriteOff   = "write-off"
+
+	dateTimeLayout = "2006-01-02 15:04:05"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// Record appends m to the ledger using tx, so the movement is committed or rolled back
+// together with the quantity change it describes.
+func Record(ctx context.Context, tx *sql.Tx, m domain.StockMovement) error {
+	query := "INSERT INTO stock_movements (movement_type, product_batch_id, section_id, quantity, employee_id, reference_document, created_at) VALUES (?, ?, ?, ?, ?, ?, UTC_TIMESTAMP());"
+	_, err := tx.ExecContext(ctx, query,
+		m.Type,
+		sql.NullInt64{Int64: int64(m.ProductBatchID), Valid: m.ProductBatchID != 0},
+		m.SectionID,
+		m.Quantity,
+		sql.NullInt64{Int64: int64(m.EmployeeID), Valid: m.EmployeeID != 0},
+		sql.NullString{String: m.ReferenceDocument, Valid: m.ReferenceDocument != ""},
+	)
+	return err
+}
+
+func (r *repository) GetAll(ctx context.Context, filter domain.StockMovementFilter) ([]domain.StockMovement, int, error) {
+	where := " WHERE 1=1"
+	args := []interface{}{}
+	if filter.Type != "" {
+		where += " AND movement_type=?"
+		args = append(args, filter.Type)
+	}
+	if filter.ProductBatchID != 0 {
+		where += " AND product_batch_id=?"
+		args = append(args, filter.ProductBatchID)
+	}
+	if filter.SectionID != 0 {
+		where += " AND section_id=?"
+		args = append(args, filter.SectionID)
+	}
+	if filter.EmployeeID != 0 {
+		where += " AND employee_id=?"
+		args = append(args, filter.EmployeeID)
+	}
+	if !filter.From.IsZero() {
+		where += " AND created_at >= ?"
+		args = append(args, filter.From.UTC().Format(dateTimeLayout))
+	}
+	if !filter.To.IsZero() {
+		where += " AND created_at < ?"
+		args = append(args, filter.To.UTC().Format(dateTimeLayout))
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM stock_movements"+where+";", args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT id, movement_type, product_batch_id, section_id, quantity, employee_id, reference_document, created_at FROM stock_movements" + where + " ORDER BY created_at, id LIMIT ? OFFSET ?;"
+	rows, err := r.db.Query(query, append(args, filter.Limit, (filter.Page-1)*filter.Limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := []domain.StockMovement{}
+
+	for rows.Next() {
+		m := domain.StockMovement{}
+		var productBatchId, employeeId sql.NullInt64
+		var referenceDocument sql.NullString
+		var createdAt string
+		if err := rows.Scan(&m.ID, &m.Type, &productBatchId, &m.SectionID, &m.Quantity, &employeeId, &referenceDocument, &createdAt); err != nil {
+			return nil, 0, err
+		}
+		m.ProductBatchID = int(productBatchId.Int64)
+		m.EmployeeID = int(employeeId.Int64)
+		m.ReferenceDocument = referenceDocument.String
+		m.CreatedAt, err = time.Parse(dateTimeLayout, createdAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, total, nil
+}
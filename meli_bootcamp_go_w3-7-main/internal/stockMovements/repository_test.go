@@ -0,0 +1,109 @@
+package stockMovements
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var movementColumns = []string{"id", "movement_type", "product_batch_id", "section_id", "quantity", "employee_id", "reference_document", "created_at"}
+
+func TestGetAllFiltered(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	to, _ := time.Parse("2006-01-02", "2022-04-05")
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM stock_movements WHERE 1=1 AND movement_type=\\? AND section_id=\\? AND created_at < \\?").
+		WithArgs(TypePick, 1, "2022-04-05 00:00:00").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery("WHERE 1=1 AND movement_type=\\? AND section_id=\\? AND created_at < \\? ORDER BY created_at, id LIMIT \\? OFFSET \\?").
+		WithArgs(TypePick, 1, "2022-04-05 00:00:00", 20, 0).
+		WillReturnRows(sqlmock.NewRows(movementColumns).
+			AddRow(1, TypePick, 4, 1, -30, 3, "order#1", "2022-04-04 10:00:00").
+			AddRow(2, TypePick, nil, 1, -5, nil, nil, "2022-04-04 11:00:00"))
+	s := NewService(NewRepository(db))
+	page, err := s.GetAll(context.Background(), domain.StockMovementFilter{Type: TypePick, SectionID: 1, To: to})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, 20, page.Limit)
+	assert.Equal(t, 2, page.Total)
+	movements := page.Data
+	assert.Len(t, movements, 2)
+	assert.Equal(t, -30, movements[0].Quantity)
+	assert.Equal(t, "order#1", movements[0].ReferenceDocument)
+	assert.Equal(t, 0, movements[1].ProductBatchID)
+	assert.Equal(t, 0, movements[1].EmployeeID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllSecondPage(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM stock_movements WHERE 1=1 AND movement_type=\\?").
+		WithArgs(TypeWriteOff).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery("ORDER BY created_at, id LIMIT \\? OFFSET \\?").
+		WithArgs(TypeWriteOff, 5, 10).
+		WillReturnRows(sqlmock.NewRows(movementColumns).
+			AddRow(11, TypeWriteOff, 4, 1, -30, nil, nil, "2022-04-04 10:00:00").
+			AddRow(12, TypeWriteOff, 5, 1, -5, nil, nil, "2022-04-04 11:00:00"))
+	s := NewService(NewRepository(db))
+	page, err := s.GetAll(context.Background(), domain.StockMovementFilter{Type: TypeWriteOff, Page: 3, Limit: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, 12, page.Total)
+	assert.Len(t, page.Data, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllInvalidPage(t *testing.T) {
+	db, _, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	s := NewService(NewRepository(db))
+	_, err := s.GetAll(context.Background(), domain.StockMovementFilter{Limit: 101})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+	_, err = s.GetAll(context.Background(), domain.StockMovementFilter{Page: -1})
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestGetAllEmpty(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM stock_movements").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("FROM stock_movements").WillReturnRows(sqlmock.NewRows(movementColumns))
+	s := NewService(NewRepository(db))
+	page, err := s.GetAll(context.Background(), domain.StockMovementFilter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.StockMovement{}, page.Data)
+}
+
+func TestGetAllInvalidType(t *testing.T) {
+	db, _, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	s := NewService(NewRepository(db))
+	_, err := s.GetAll(context.Background(), domain.StockMovementFilter{Type: "theft"})
+	assert.ErrorIs(t, err, ErrInvalidType)
+}
+
+func TestRecordInTransaction(t *testing.T) {
+	db, mock, sqlMockErr := sqlmock.New()
+	assert.Nil(t, sqlMockErr)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO stock_movements").
+		WithArgs(TypeAdjustment, nil, 1, 10, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	tx, err := db.Begin()
+	assert.Nil(t, err)
+	assert.Nil(t, Record(context.Background(), tx, domain.StockMovement{Type: TypeAdjustment, SectionID: 1, Quantity: 10}))
+	assert.Nil(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,67 @@
+package stockMovements
+
+import (
+	"context"
+	"errors"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Errors
+var (
+	ErrInvalidType   = errors.New("el tipo de movimiento debe ser inbound, pick, adjustment, transfer o write-off")
+	ErrInvalidRange  = errors.New("la fecha from debe ser anterior a la fecha to")
+	ErrInvalidFilter = errors.New("page y limit deben ser positivos y limit como maximo 100")
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type Service interface {
+	GetAll(ctx context.Context, filter domain.StockMovementFilter) (domain.StockMovementsPage, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) GetAll(ctx context.Context, filter domain.StockMovementFilter) (domain.StockMovementsPage, error) {
+	switch filter.Type {
+	case "", TypeInbound, TypePick, TypeAdjustment, TypeTransfer, TypeWriteOff:
+	default:
+		return domain.StockMovementsPage{}, ErrInvalidType
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return domain.StockMovementsPage{}, ErrInvalidRange
+	}
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+	if filter.Page < 0 || filter.Limit < 0 || filter.Limit > maxLimit {
+		return domain.StockMovementsPage{}, ErrInvalidFilter
+	}
+	movements, total, err := s.repository.GetAll(ctx, filter)
+	if err != nil {
+		return domain.StockMovementsPage{}, err
+	}
+	if movements == nil {
+		movements = []domain.StockMovement{}
+	}
+	return domain.StockMovementsPage{
+		Data:  movements,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package temperatureReadings
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Repository encapsulates the storage of the temperature readings of a section.
+type Repository interface {
+	ExistsSection(ctx context.Context, sectionId int) bool
+	Save(ctx context.Context, sectionId int, readings []domain.TemperatureReading) ([]int, error)
+	GetBySection(ctx context.Context, sectionId int, from, to time.Time) ([]domain.TemperatureReading, error)
+}
+
+const dateTimeLayout = "2006-01-02 15:04:05"
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) ExistsSection(ctx context.Context, sectionId int) bool {
+	query := "SELECT id FROM sections WHERE id=?;"
+	row := r.db.QueryRow(query, sectionId)
+	err := row.Scan(&sectionId)
+	return err == nil
+}
+
+// Save stores the readings and copies the most recent reading of the section into
+// sections.current_temperature in the same transaction.
+func (r *repository) Save(ctx context.Context, sectionId int, readings []domain.TemperatureReading) ([]int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := "INSERT INTO section_temperature_readings (section_id, temperature, read_at) VALUES (?, ?, ?);"
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	ids := make([]int, 0, len(readings))
+	for _, reading := range readings {
+		res, err := stmt.ExecContext(ctx, sectionId, reading.Temperature, reading.ReadAt.UTC().Format(dateTimeLayout))
+		if err != nil {
+			return nil, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, int(id))
+	}
+
+	query = "UPDATE sections SET current_temperature=(SELECT ROUND(temperature) FROM section_temperature_readings WHERE section_id=? ORDER BY read_at DESC, id DESC LIMIT 1) WHERE id=?;"
+	if _, err := tx.ExecContext(ctx, query, sectionId, sectionId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *repository) GetBySection(ctx context.Context, sectionId int, from, to time.Time) ([]domain.TemperatureReading, error) {
+	query := "SELECT id, section_id, temperature, read_at FROM section_temperature_readings WHERE section_id=? AND read_at >= ? AND read_at < ? ORDER BY read_at;"
+	rows, err := r.db.Query(query, sectionId, from.UTC().Format(dateTimeLayout), to.UTC().Format(dateTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var readings []domain.TemperatureReading
+
+	for rows.Next() {
+		reading := domain.TemperatureReading{}
+		var readAt string
+		if err := rows.Scan(&reading.ID, &reading.SectionID, &reading.Temperature, &readAt); err != nil {
+			return nil, err
+		}
+		reading.ReadAt, err = time.Parse(dateTimeLayout, readAt)
+		if err != nil {
+			return nil, err
+		}
+		readings = append(readings, reading)
+	}
+
+	return readings, nil
+}
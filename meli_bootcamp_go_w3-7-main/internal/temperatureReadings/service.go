@@ -0,0 +1,94 @@
+package temperatureReadings
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+)
+
+// Errors
+var (
+	ErrSectionNotFound = errors.New("la seccion no fue encontrada")
+	ErrNoReadings      = errors.New("se requiere al menos una lectura de temperatura")
+	ErrInvalidRange    = errors.New("el rango de fechas o el intervalo no es valido")
+)
+
+type Service interface {
+	Save(ctx context.Context, sectionId int, readings []domain.TemperatureReading) ([]domain.TemperatureReading, error)
+	GetAggregates(ctx context.Context, sectionId int, from, to time.Time, interval time.Duration) ([]domain.TemperatureAggregate, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s *service) Save(ctx context.Context, sectionId int, readings []domain.TemperatureReading) ([]domain.TemperatureReading, error) {
+	if len(readings) == 0 {
+		return nil, ErrNoReadings
+	}
+	if !s.repository.ExistsSection(ctx, sectionId) {
+		return nil, ErrSectionNotFound
+	}
+	ids, err := s.repository.Save(ctx, sectionId, readings)
+	if err != nil {
+		return nil, err
+	}
+	saved := make([]domain.TemperatureReading, len(readings))
+	for i, reading := range readings {
+		reading.ID = ids[i]
+		reading.SectionID = sectionId
+		saved[i] = reading
+	}
+	return saved, nil
+}
+
+// GetAggregates groups the readings of [from, to) into consecutive windows of the given
+// interval, starting at from, and returns min/max/avg for every window that has readings.
+func (s *service) GetAggregates(ctx context.Context, sectionId int, from, to time.Time, interval time.Duration) ([]domain.TemperatureAggregate, error) {
+	if interval <= 0 || !from.Before(to) {
+		return nil, ErrInvalidRange
+	}
+	if !s.repository.ExistsSection(ctx, sectionId) {
+		return nil, ErrSectionNotFound
+	}
+	readings, err := s.repository.GetBySection(ctx, sectionId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates := []domain.TemperatureAggregate{}
+	var sum float32
+	for _, reading := range readings {
+		windowStart := from.Add(reading.ReadAt.Sub(from) / interval * interval)
+		last := len(aggregates) - 1
+		if last < 0 || !aggregates[last].From.Equal(windowStart) {
+			aggregates = append(aggregates, domain.TemperatureAggregate{
+				From: windowStart,
+				To:   windowStart.Add(interval),
+				Min:  reading.Temperature,
+				Max:  reading.Temperature,
+			})
+			last++
+			sum = 0
+		}
+		aggregate := &aggregates[last]
+		if reading.Temperature < aggregate.Min {
+			aggregate.Min = reading.Temperature
+		}
+		if reading.Temperature > aggregate.Max {
+			aggregate.Max = reading.Temperature
+		}
+		sum += reading.Temperature
+		aggregate.Readings++
+		aggregate.Avg = sum / float32(aggregate.Readings)
+	}
+	return aggregates, nil
+}
@@ -0,0 +1,93 @@
+package temperatureReadings
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type repoM struct {
+	mock.Mock
+}
+
+func (r *repoM) ExistsSection(ctx context.Context, sectionId int) bool {
+	args := r.Called(ctx, sectionId)
+	return args.Bool(0)
+}
+
+func (r *repoM) Save(ctx context.Context, sectionId int, readings []domain.TemperatureReading) ([]int, error) {
+	args := r.Called(ctx, sectionId, readings)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (r *repoM) GetBySection(ctx context.Context, sectionId int, from, to time.Time) ([]domain.TemperatureReading, error) {
+	args := r.Called(ctx, sectionId, from, to)
+	return args.Get(0).([]domain.TemperatureReading), args.Error(1)
+}
+
+func readingAt(value string, temperature float32) domain.TemperatureReading {
+	readAt, _ := time.Parse(time.RFC3339, value)
+	return domain.TemperatureReading{SectionID: 1, Temperature: temperature, ReadAt: readAt}
+}
+
+func TestCreateOk(t *testing.T) {
+	readings := []domain.TemperatureReading{readingAt("2022-01-06T10:00:00Z", -18), readingAt("2022-01-06T10:05:00Z", -17.5)}
+	repo := new(repoM)
+	repo.On("ExistsSection", mock.Anything, 1).Return(true)
+	repo.On("Save", mock.Anything, 1, readings).Return([]int{7, 8}, nil)
+	s := NewService(repo)
+	saved, err := s.Save(context.Background(), 1, readings)
+	assert.NoError(t, err)
+	assert.Len(t, saved, 2)
+	assert.Equal(t, 7, saved[0].ID)
+	assert.Equal(t, 8, saved[1].ID)
+	assert.Equal(t, float32(-17.5), saved[1].Temperature)
+}
+
+func TestCreateSectionNotFound(t *testing.T) {
+	repo := new(repoM)
+	repo.On("ExistsSection", mock.Anything, 9).Return(false)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), 9, []domain.TemperatureReading{readingAt("2022-01-06T10:00:00Z", -18)})
+	assert.ErrorIs(t, err, ErrSectionNotFound)
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateWithoutReadings(t *testing.T) {
+	repo := new(repoM)
+	s := NewService(repo)
+	_, err := s.Save(context.Background(), 1, nil)
+	assert.ErrorIs(t, err, ErrNoReadings)
+}
+
+func TestGetAggregates(t *testing.T) {
+	from, _ := time.Parse(time.RFC3339, "2022-01-06T10:00:00Z")
+	to := from.Add(3 * time.Hour)
+	repo := new(repoM)
+	repo.On("ExistsSection", mock.Anything, 1).Return(true)
+	repo.On("GetBySection", mock.Anything, 1, from, to).Return([]domain.TemperatureReading{
+		readingAt("2022-01-06T10:00:00Z", -18),
+		readingAt("2022-01-06T10:30:00Z", -16),
+		readingAt("2022-01-06T10:59:59Z", -20),
+		readingAt("2022-01-06T12:10:00Z", -15),
+	}, nil)
+	s := NewService(repo)
+	aggregates, err := s.GetAggregates(context.Background(), 1, from, to, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.TemperatureAggregate{
+		{From: from, To: from.Add(time.Hour), Min: -20, Max: -16, Avg: -18, Readings: 3},
+		{From: from.Add(2 * time.Hour), To: from.Add(3 * time.Hour), Min: -15, Max: -15, Avg: -15, Readings: 1},
+	}, aggregates)
+}
+
+func TestGetAggregatesInvalidRange(t *testing.T) {
+	from, _ := time.Parse(time.RFC3339, "2022-01-06T10:00:00Z")
+	repo := new(repoM)
+	s := NewService(repo)
+	_, err := s.GetAggregates(context.Background(), 1, from, from.Add(-time.Hour), time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
@@ -3,6 +3,7 @@ package warehouse
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 )
@@ -15,6 +16,9 @@ type Repository interface {
 	Save(ctx context.Context, w domain.Warehouse) (int, error)
 	Update(ctx context.Context, w domain.Warehouse) error
 	Delete(ctx context.Context, id int) error
+	GetSectionsOccupancy(ctx context.Context, warehouseId int) ([]domain.SectionOccupancy, error)
+	GetDependents(ctx context.Context, id int) (domain.WarehouseDependents, error)
+	DeleteCascade(ctx context.Context, id int) error
 }
 
 type repository struct {
@@ -127,3 +131,148 @@ func (r *repository) Delete(ctx context.Context, id int) error {
 
 	return nil
 }
+
+func (r *repository) GetSectionsOccupancy(ctx context.Context, warehouseId int) ([]domain.SectionOccupancy, error) {
+	query := "SELECT id, section_number, current_capacity, minimum_capacity, maximum_capacity FROM sections WHERE warehouse_id=? ORDER BY section_number, id;"
+	rows, err := r.db.QueryContext(ctx, query, warehouseId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sections := []domain.SectionOccupancy{}
+
+	for rows.Next() {
+		s := domain.SectionOccupancy{}
+		if err := rows.Scan(&s.SectionID, &s.SectionNumber, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity); err != nil {
+			return nil, err
+		}
+		sections = append(sections, s)
+	}
+
+	return sections, rows.Err()
+}
+
+func (r *repository) GetDependents(ctx context.Context, id int) (domain.WarehouseDependents, error) {
+	dependents := domain.WarehouseDependents{}
+	var err error
+	dependents.SectionIDs, err = r.getIDs(ctx, "SELECT id FROM sections WHERE warehouse_id=? ORDER BY id;", id)
+	if err != nil {
+		return domain.WarehouseDependents{}, err
+	}
+	dependents.EmployeeIDs, err = r.getIDs(ctx, "SELECT id FROM employees WHERE warehouse_id=? ORDER BY id;", id)
+	if err != nil {
+		return domain.WarehouseDependents{}, err
+	}
+	dependents.ProductBatchIDs, err = r.getIDs(ctx, "SELECT pb.id FROM product_batches pb INNER JOIN sections s ON s.id = pb.section_id WHERE s.warehouse_id=? ORDER BY pb.id;", id)
+	if err != nil {
+		return domain.WarehouseDependents{}, err
+	}
+	dependents.InboundOrderIDs, err = r.getIDs(ctx, "SELECT id FROM inbound_orders WHERE warehouse_id=? ORDER BY id;", id)
+	if err != nil {
+		return domain.WarehouseDependents{}, err
+	}
+	query := "SELECT COUNT(*) FROM section_temperature_readings r INNER JOIN sections s ON s.id = r.section_id WHERE s.warehouse_id=?;"
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&dependents.TemperatureReadings); err != nil {
+		return domain.WarehouseDependents{}, err
+	}
+	query = "SELECT COUNT(*) FROM alerts a INNER JOIN sections s ON s.id = a.section_id WHERE s.warehouse_id=?;"
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&dependents.Alerts); err != nil {
+		return domain.WarehouseDependents{}, err
+	}
+	dependents.Stock, dependents.StockMovements, dependents.ShipmentBatches, err = stockHistory(ctx, r.db, id, "")
+	if err != nil {
+		return domain.WarehouseDependents{}, err
+	}
+	return dependents, nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// stockHistory returns the stock held by the sections of the warehouse and how many stock
+// movements and shipment batches reference them or their batches. lock is appended to
+// every query.
+func stockHistory(ctx context.Context, q queryRower, id int, lock string) (stock, movements, shipmentBatches int, err error) {
+	query := "SELECT COALESCE(SUM(current_capacity), 0) FROM sections WHERE warehouse_id=?" + lock + ";"
+	if err = q.QueryRowContext(ctx, query, id).Scan(&stock); err != nil {
+		return 0, 0, 0, err
+	}
+	query = "SELECT COUNT(*) FROM stock_movements m INNER JOIN sections s ON s.id = m.section_id WHERE s.warehouse_id=?" + lock + ";"
+	if err = q.QueryRowContext(ctx, query, id).Scan(&movements); err != nil {
+		return 0, 0, 0, err
+	}
+	query = "SELECT COUNT(*) FROM shipment_batches sb INNER JOIN product_batches pb ON pb.id = sb.product_batch_id INNER JOIN sections s ON s.id = pb.section_id WHERE s.warehouse_id=?" + lock + ";"
+	if err = q.QueryRowContext(ctx, query, id).Scan(&shipmentBatches); err != nil {
+		return 0, 0, 0, err
+	}
+	return stock, movements, shipmentBatches, nil
+}
+
+func (r *repository) getIDs(ctx context.Context, query string, args ...interface{}) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// DeleteCascade removes the warehouse together with its employees, their assignments to
+// it, its inbound orders and sections, and the product batches, temperature readings and
+// alerts of those sections, in a single transaction. It returns ErrHasStock and deletes
+// nothing while the sections hold stock or stock movements or shipment batches reference
+// them, so the ledger and the shipments never point at deleted rows.
+func (r *repository) DeleteCascade(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "SELECT id FROM warehouses WHERE id=? FOR UPDATE;"
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	stock, movements, shipmentBatches, err := stockHistory(ctx, tx, id, " FOR UPDATE")
+	if err != nil {
+		return err
+	}
+	if stock > 0 || movements > 0 || shipmentBatches > 0 {
+		return fmt.Errorf("%w: stock %d, stock movements %d, shipment batches %d", ErrHasStock, stock, movements, shipmentBatches)
+	}
+
+	queries := []string{
+		"DELETE FROM employee_assignments WHERE warehouse_id=?;",
+		"DELETE FROM employees WHERE warehouse_id=?;",
+		"DELETE FROM inbound_orders WHERE warehouse_id=?;",
+		"DELETE r FROM section_temperature_readings r INNER JOIN sections s ON s.id = r.section_id WHERE s.warehouse_id=?;",
+		"DELETE a FROM alerts a INNER JOIN sections s ON s.id = a.section_id WHERE s.warehouse_id=?;",
+		"DELETE pb FROM product_batches pb INNER JOIN sections s ON s.id = pb.section_id WHERE s.warehouse_id=?;",
+		"DELETE FROM sections WHERE warehouse_id=?;",
+		"DELETE FROM warehouses WHERE id=?;",
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,99 @@
+package warehouse
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDependentsListsEveryReference(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SELECT id FROM sections WHERE warehouse_id=\\?").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery("SELECT id FROM employees WHERE warehouse_id=\\?").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT pb.id FROM product_batches pb INNER JOIN sections s ON s.id = pb.section_id WHERE s.warehouse_id=\\?").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(5))
+	mock.ExpectQuery("SELECT id FROM inbound_orders WHERE warehouse_id=\\?").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM section_temperature_readings").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(30))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM alerts").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM\\(current_capacity\\), 0\\) FROM sections WHERE warehouse_id=\\?;").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(40))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM stock_movements").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM shipment_batches").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	repo := NewRepository(db)
+	dependents, err := repo.GetDependents(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WarehouseDependents{
+		SectionIDs:          []int{2},
+		EmployeeIDs:         []int{},
+		ProductBatchIDs:     []int{4, 5},
+		InboundOrderIDs:     []int{8},
+		TemperatureReadings: 30,
+		Alerts:              1,
+		Stock:               40,
+		StockMovements:      3,
+		ShipmentBatches:     1,
+	}, dependents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCascadeRemovesSectionDependents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM warehouses WHERE id=\\? FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectStockHistory(mock, 0, 0, 0)
+	mock.ExpectExec("DELETE FROM employee_assignments WHERE warehouse_id=\\?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM employees").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM inbound_orders").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE r FROM section_temperature_readings").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 30))
+	mock.ExpectExec("DELETE a FROM alerts").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE pb FROM product_batches").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM sections").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM warehouses").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewRepository(db)
+	assert.NoError(t, repo.DeleteCascade(context.Background(), 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteCascadeRefusesStockHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM warehouses WHERE id=\\? FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectStockHistory(mock, 0, 2, 0)
+	mock.ExpectRollback()
+
+	repo := NewRepository(db)
+	err = repo.DeleteCascade(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrHasStock)
+	assert.Contains(t, err.Error(), "stock movements 2")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func expectStockHistory(mock sqlmock.Sqlmock, stock, movements, shipmentBatches int) {
+	mock.ExpectQuery("FROM sections WHERE warehouse_id=\\? FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(stock))
+	mock.ExpectQuery("FROM stock_movements m INNER JOIN sections s ON s.id = m.section_id WHERE s.warehouse_id=\\? FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(movements))
+	mock.ExpectQuery("FROM shipment_batches sb .* WHERE s.warehouse_id=\\? FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(shipmentBatches))
+}
@@ -2,16 +2,25 @@ package warehouse
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"math"
 
 	"github.com/extlurosell/meli_bootcamp_go_w3-7/internal/domain"
 )
 
 // Errors
 var (
-	ErrNotFound = errors.New("warehouse not found")
+	ErrNotFound      = errors.New("warehouse not found")
+	ErrHasDependents = errors.New("warehouse has dependents")
+	ErrHasStock      = errors.New("warehouse holds stock or stock history")
 )
 
+// NearlyFullThreshold is the utilisation percentage above which a section is
+// reported as nearly full.
+const NearlyFullThreshold = 90.0
+
 type Service interface {
 	Get(ctx context.Context, id int) (domain.Warehouse, error)
 	GetAll(ctx context.Context) ([]domain.Warehouse, error)
@@ -19,6 +28,8 @@ type Service interface {
 	Exists(ctx context.Context, wc string) bool
 	Update(ctx context.Context, w domain.Warehouse) error
 	Delete(ctx context.Context, id int) error
+	DeleteCascade(ctx context.Context, id int) error
+	GetOccupancy(ctx context.Context, id int) (domain.WarehouseOccupancy, error)
 }
 
 type service struct {
@@ -56,6 +67,67 @@ func (s *service) Update(ctx context.Context, w domain.Warehouse) error {
 	return s.repository.Update(ctx, w)
 }
 
+// Delete removes the warehouse only when nothing references it: no section, employee or
+// inbound order, nor batch, temperature reading or alert of its sections.
 func (s *service) Delete(ctx context.Context, id int) error {
+	d, err := s.repository.GetDependents(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(d.SectionIDs) > 0 || len(d.EmployeeIDs) > 0 || len(d.ProductBatchIDs) > 0 || len(d.InboundOrderIDs) > 0 || d.TemperatureReadings > 0 || d.Alerts > 0 || d.Stock > 0 || d.StockMovements > 0 || d.ShipmentBatches > 0 {
+		return fmt.Errorf("%w: sections %v, employees %v, product batches %v, inbound orders %v, temperature readings %d, alerts %d, stock %d, stock movements %d, shipment batches %d",
+			ErrHasDependents, d.SectionIDs, d.EmployeeIDs, d.ProductBatchIDs, d.InboundOrderIDs, d.TemperatureReadings, d.Alerts, d.Stock, d.StockMovements, d.ShipmentBatches)
+	}
 	return s.repository.Delete(ctx, id)
 }
+
+func (s *service) DeleteCascade(ctx context.Context, id int) error {
+	return s.repository.DeleteCascade(ctx, id)
+}
+
+func (s *service) GetOccupancy(ctx context.Context, id int) (domain.WarehouseOccupancy, error) {
+	w, err := s.repository.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.WarehouseOccupancy{}, ErrNotFound
+		}
+		return domain.WarehouseOccupancy{}, err
+	}
+	sections, err := s.repository.GetSectionsOccupancy(ctx, id)
+	if err != nil {
+		return domain.WarehouseOccupancy{}, err
+	}
+	occupancy := domain.WarehouseOccupancy{
+		WarehouseID:          w.ID,
+		WarehouseCode:        w.WarehouseCode,
+		MinimumCapacity:      w.MinimumCapacity,
+		Sections:             sections,
+		SectionsBelowMinimum: []int{},
+		SectionsNearlyFull:   []int{},
+	}
+	for i := range occupancy.Sections {
+		section := &occupancy.Sections[i]
+		section.Utilization = utilization(section.CurrentCapacity, section.MaximumCapacity)
+		section.BelowMinimum = section.CurrentCapacity < section.MinimumCapacity
+		section.NearlyFull = section.Utilization > NearlyFullThreshold
+		if section.BelowMinimum {
+			occupancy.SectionsBelowMinimum = append(occupancy.SectionsBelowMinimum, section.SectionID)
+		}
+		if section.NearlyFull {
+			occupancy.SectionsNearlyFull = append(occupancy.SectionsNearlyFull, section.SectionID)
+		}
+		occupancy.CurrentCapacity += section.CurrentCapacity
+		occupancy.MaximumCapacity += section.MaximumCapacity
+	}
+	occupancy.Utilization = utilization(occupancy.CurrentCapacity, occupancy.MaximumCapacity)
+	occupancy.BelowMinimum = occupancy.CurrentCapacity < occupancy.MinimumCapacity
+	return occupancy, nil
+}
+
+// utilization returns current as a percentage of maximum, rounded to two decimals.
+func utilization(current, maximum int) float64 {
+	if maximum <= 0 {
+		return 0
+	}
+	return math.Round(float64(current)/float64(maximum)*10000) / 100
+}
@@ -2,6 +2,7 @@ package warehouse
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -44,6 +45,21 @@ func (m *repoM) Delete(ctx context.Context, id int) error {
 	return args.Error(0)
 }
 
+func (m *repoM) GetDependents(ctx context.Context, id int) (domain.WarehouseDependents, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.WarehouseDependents), args.Error(1)
+}
+
+func (m *repoM) DeleteCascade(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *repoM) GetSectionsOccupancy(ctx context.Context, warehouseId int) ([]domain.SectionOccupancy, error) {
+	args := m.Called(ctx, warehouseId)
+	return args.Get(0).([]domain.SectionOccupancy), args.Error(1)
+}
+
 func TestCreateWarehouseOK(t *testing.T) {
 
 	repo := new(repoM)
@@ -164,6 +180,7 @@ func TestUpdateNonExistentWarehouse(t *testing.T) {
 
 func TestDeleteNonExistentWarehouse(t *testing.T) {
 	repo := new(repoM)
+	repo.On("GetDependents", mock.Anything, 1).Return(domain.WarehouseDependents{}, nil)
 	repo.On("Delete", mock.Anything, mock.Anything).Return(errors.New("warehouse id not found"))
 	serviceT := NewService(repo)
 	ctx := context.Background()
@@ -173,6 +190,7 @@ func TestDeleteNonExistentWarehouse(t *testing.T) {
 
 func TestDeleteOK(t *testing.T) {
 	repo := new(repoM)
+	repo.On("GetDependents", mock.Anything, 1).Return(domain.WarehouseDependents{}, nil)
 	repo.On("Delete", mock.Anything, mock.Anything).Return(nil)
 	serviceT := NewService(repo)
 	ctx := context.Background()
@@ -180,6 +198,26 @@ func TestDeleteOK(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestDeleteWarehouseWithDependents(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetDependents", mock.Anything, 1).Return(domain.WarehouseDependents{SectionIDs: []int{2, 3}, EmployeeIDs: []int{}}, nil)
+	serviceT := NewService(repo)
+	err := serviceT.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrHasDependents)
+	assert.Contains(t, err.Error(), "sections [2 3]")
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteWarehouseWithInboundOrders(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetDependents", mock.Anything, 1).Return(domain.WarehouseDependents{InboundOrderIDs: []int{7}}, nil)
+	serviceT := NewService(repo)
+	err := serviceT.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrHasDependents)
+	assert.Contains(t, err.Error(), "inbound orders [7]")
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
 func TestCreateWarehouseError(t *testing.T) {
 	warehouseRequest := domain.Warehouse{
 		Address:            "Monroe 860",
@@ -195,3 +233,53 @@ func TestCreateWarehouseError(t *testing.T) {
 	_, err := serviceT.Save(ctx, warehouseRequest)
 	assert.NotNil(t, err)
 }
+
+func TestGetOccupancyFlagsSections(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1, WarehouseCode: "CAD", MinimumCapacity: 100}, nil)
+	repo.On("GetSectionsOccupancy", mock.Anything, 1).Return([]domain.SectionOccupancy{
+		{SectionID: 1, SectionNumber: 10, CurrentCapacity: 5, MinimumCapacity: 10, MaximumCapacity: 100},
+		{SectionID: 2, SectionNumber: 20, CurrentCapacity: 91, MinimumCapacity: 10, MaximumCapacity: 100},
+		{SectionID: 3, SectionNumber: 30, CurrentCapacity: 2, MinimumCapacity: 1, MaximumCapacity: 3},
+	}, nil)
+	serviceT := NewService(repo)
+	occupancy, err := serviceT.GetOccupancy(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 98, occupancy.CurrentCapacity)
+	assert.Equal(t, 203, occupancy.MaximumCapacity)
+	assert.Equal(t, 48.28, occupancy.Utilization)
+	assert.True(t, occupancy.BelowMinimum)
+	assert.Equal(t, 5.0, occupancy.Sections[0].Utilization)
+	assert.Equal(t, 66.67, occupancy.Sections[2].Utilization)
+	assert.Equal(t, []int{1}, occupancy.SectionsBelowMinimum)
+	assert.Equal(t, []int{2}, occupancy.SectionsNearlyFull)
+}
+
+func TestGetOccupancyWithoutSections(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 1).Return(domain.Warehouse{ID: 1}, nil)
+	repo.On("GetSectionsOccupancy", mock.Anything, 1).Return([]domain.SectionOccupancy{}, nil)
+	serviceT := NewService(repo)
+	occupancy, err := serviceT.GetOccupancy(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, occupancy.Utilization)
+	assert.Empty(t, occupancy.SectionsNearlyFull)
+}
+
+func TestGetOccupancyNonExistentWarehouse(t *testing.T) {
+	repo := new(repoM)
+	repo.On("Get", mock.Anything, 9).Return(domain.Warehouse{}, sql.ErrNoRows)
+	serviceT := NewService(repo)
+	_, err := serviceT.GetOccupancy(context.Background(), 9)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDeleteWarehouseWithStockHistory(t *testing.T) {
+	repo := new(repoM)
+	repo.On("GetDependents", mock.Anything, 1).Return(domain.WarehouseDependents{StockMovements: 4, ShipmentBatches: 1}, nil)
+	s := NewService(repo)
+	err := s.Delete(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrHasDependents)
+	assert.Contains(t, err.Error(), "stock movements 4, shipment batches 1")
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,94 @@
+-- Moves the free-text province_name and country_name of localities into the
+-- countries and provinces tables and makes localities reference provinces by id.
+-- Names are trimmed and matched with the column collation, so "Argentina" and
+-- "argentina " end up as the same country. It also makes zip_code a mandatory,
+-- unique varchar(10), adding the column where the schema never had it.
+use melisprint;
+
+CREATE TABLE countries
+(
+    `id` int not null primary key auto_increment,
+    country_name varchar(100) not null
+);
+
+create unique index countries_country_name_uindex
+    on countries (country_name);
+
+CREATE TABLE provinces
+(
+    `id` int not null primary key auto_increment,
+    province_name varchar(100) not null,
+    country_id    int          not null,
+    constraint provinces_countries_id_fk
+        foreign key (country_id) references countries (id)
+);
+
+create unique index provinces_country_province_name_uindex
+    on provinces (country_id, province_name);
+
+INSERT INTO countries (country_name)
+SELECT DISTINCT TRIM(country_name)
+FROM localities
+WHERE TRIM(COALESCE(country_name, '')) <> '';
+
+INSERT INTO provinces (province_name, country_id)
+SELECT DISTINCT TRIM(l.province_name), c.id
+FROM localities l
+         INNER JOIN countries c ON c.country_name = TRIM(l.country_name)
+WHERE TRIM(COALESCE(l.province_name, '')) <> '';
+
+alter table localities
+    add province_id int null;
+
+UPDATE localities l
+    INNER JOIN countries c ON c.country_name = TRIM(l.country_name)
+    INNER JOIN provinces p ON p.country_id = c.id AND p.province_name = TRIM(l.province_name)
+SET l.province_id = p.id;
+
+-- Localities without a province or a country cannot be normalised. List them
+-- with the query below and fix them by hand before running the rest: the
+-- not null change fails while any of them is left.
+-- SELECT id, zip_code, locality_name, province_name, country_name FROM localities WHERE province_id IS NULL;
+
+alter table localities
+    modify province_id int not null;
+
+alter table localities
+    add constraint localities_provinces_id_fk
+        foreign key (province_id) references provinces (id);
+
+alter table localities
+    drop column province_name,
+    drop column country_name;
+
+-- zip_code was written by the application but missing from the original schema,
+-- so it only exists where it was added by hand. Add it when it is not there.
+SET @zip_code_exists = (SELECT COUNT(*)
+                        FROM information_schema.columns
+                        WHERE table_schema = DATABASE()
+                          AND table_name = 'localities'
+                          AND column_name = 'zip_code');
+SET @add_zip_code = IF(@zip_code_exists = 0,
+                       'alter table localities add zip_code varchar(10) null after id',
+                       'alter table localities modify zip_code text null');
+PREPARE add_zip_code FROM @add_zip_code;
+EXECUTE add_zip_code;
+DEALLOCATE PREPARE add_zip_code;
+
+UPDATE localities
+SET zip_code = NULLIF(TRIM(zip_code), '');
+
+-- There is no source to backfill missing zip codes from. List the localities
+-- without one, with one longer than 10 characters or sharing it with another
+-- locality, and fix them by hand before running the rest: the not null change
+-- and the unique index fail while any of them is left.
+-- SELECT id, zip_code, locality_name FROM localities
+-- WHERE zip_code IS NULL
+--    OR CHAR_LENGTH(zip_code) > 10
+--    OR zip_code IN (SELECT zip_code FROM (SELECT zip_code FROM localities GROUP BY zip_code HAVING COUNT(*) > 1) d);
+
+alter table localities
+    modify zip_code varchar(10) not null;
+
+create unique index localities_zip_code_uindex
+    on localities (zip_code);
@@ -0,0 +1,36 @@
+-- Adds the received quantity to inbound orders and makes order_number a bounded,
+-- mandatory and unique column, so concurrent inserts of the same inbound order
+-- cannot both pass the existence check.
+use melisprint;
+
+-- Orders stored before the column existed did not add stock: the batch they
+-- reference was received with its initial quantity, so that is their quantity.
+SET @quantity_exists = (SELECT COUNT(*)
+                        FROM information_schema.columns
+                        WHERE table_schema = DATABASE()
+                          AND table_name = 'inbound_orders'
+                          AND column_name = 'quantity');
+SET @add_quantity = IF(@quantity_exists = 0,
+                       'alter table inbound_orders add quantity int not null default 0',
+                       'DO 0');
+PREPARE add_quantity FROM @add_quantity;
+EXECUTE add_quantity;
+DEALLOCATE PREPARE add_quantity;
+
+UPDATE inbound_orders i
+    INNER JOIN product_batches pb ON pb.id = i.product_batch_id
+SET i.quantity = pb.initial_quantity
+WHERE i.quantity = 0;
+
+-- The ALTER fails while any of these rows exist. Orders without number or with
+-- a repeated one have to be renumbered by hand before running it:
+-- SELECT id, order_number FROM inbound_orders
+-- WHERE order_number IS NULL
+--    OR CHAR_LENGTH(order_number) > 50
+--    OR order_number IN (SELECT order_number FROM (SELECT order_number FROM inbound_orders GROUP BY order_number HAVING COUNT(*) > 1) d);
+
+ALTER TABLE inbound_orders
+    MODIFY order_number varchar(50) not null;
+
+create unique index inbound_orders_order_number_uindex
+    on inbound_orders (order_number);
@@ -0,0 +1,24 @@
+-- Creates the employee_assignments history and opens one assignment per existing
+-- employee in its current warehouse, so GET /employees/:id/assignments and
+-- GET /warehouses/:id/employees also cover employees hired before the history.
+-- Their real start date is unknown, so the assignment starts when the migration
+-- runs. It can be run again: employees with an open assignment are skipped.
+use melisprint;
+
+create table if not exists employee_assignments(
+    `id` int not null primary key auto_increment,
+    employee_id int not null,
+    warehouse_id int not null,
+    start_date datetime not null,
+    end_date datetime null,
+    index employee_assignments_employee_id_index (employee_id, start_date),
+    index employee_assignments_warehouse_id_index (warehouse_id, start_date)
+);
+
+INSERT INTO employee_assignments (employee_id, warehouse_id, start_date)
+SELECT e.id, e.warehouse_id, UTC_TIMESTAMP()
+FROM employees e
+WHERE NOT EXISTS (SELECT 1
+                  FROM employee_assignments a
+                  WHERE a.employee_id = e.id
+                    AND a.end_date IS NULL);
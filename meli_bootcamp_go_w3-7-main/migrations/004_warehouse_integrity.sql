@@ -0,0 +1,47 @@
+-- Makes section numbers unique within each warehouse and makes sections and
+-- employees reference an existing warehouse. It can be run again: the index and
+-- the foreign keys that already exist are skipped.
+use melisprint;
+
+-- The index and the foreign keys fail while any of these rows exist. Repeated
+-- section numbers have to be renumbered, and sections or employees of missing
+-- warehouses moved or deleted, by hand before running it:
+-- SELECT warehouse_id, section_number, COUNT(*) FROM sections GROUP BY warehouse_id, section_number HAVING COUNT(*) > 1;
+-- SELECT id, warehouse_id FROM sections WHERE warehouse_id NOT IN (SELECT id FROM warehouses);
+-- SELECT id, warehouse_id FROM employees WHERE warehouse_id NOT IN (SELECT id FROM warehouses);
+
+SET @section_number_exists = (SELECT COUNT(*)
+                              FROM information_schema.statistics
+                              WHERE table_schema = DATABASE()
+                                AND table_name = 'sections'
+                                AND index_name = 'sections_warehouse_section_number_uindex');
+SET @add_section_number = IF(@section_number_exists = 0,
+                             'create unique index sections_warehouse_section_number_uindex on sections (warehouse_id, section_number)',
+                             'DO 0');
+PREPARE add_section_number FROM @add_section_number;
+EXECUTE add_section_number;
+DEALLOCATE PREPARE add_section_number;
+
+SET @sections_fk_exists = (SELECT COUNT(*)
+                           FROM information_schema.table_constraints
+                           WHERE table_schema = DATABASE()
+                             AND table_name = 'sections'
+                             AND constraint_name = 'sections_warehouses_id_fk');
+SET @add_sections_fk = IF(@sections_fk_exists = 0,
+                          'alter table sections add constraint sections_warehouses_id_fk foreign key (warehouse_id) references warehouses (id)',
+                          'DO 0');
+PREPARE add_sections_fk FROM @add_sections_fk;
+EXECUTE add_sections_fk;
+DEALLOCATE PREPARE add_sections_fk;
+
+SET @employees_fk_exists = (SELECT COUNT(*)
+                            FROM information_schema.table_constraints
+                            WHERE table_schema = DATABASE()
+                              AND table_name = 'employees'
+                              AND constraint_name = 'employees_warehouses_id_fk');
+SET @add_employees_fk = IF(@employees_fk_exists = 0,
+                           'alter table employees add constraint employees_warehouses_id_fk foreign key (warehouse_id) references warehouses (id)',
+                           'DO 0');
+PREPARE add_employees_fk FROM @add_employees_fk;
+EXECUTE add_employees_fk;
+DEALLOCATE PREPARE add_employees_fk;
@@ -0,0 +1,31 @@
+-- Adds manufacturing_hour and blocked to product_batches. manufacturing_hour was
+-- written by the application but missing from the original schema, so it only
+-- exists where it was added by hand. blocked marks the batches the expiry job
+-- took out of stock. It can be run again: existing columns are skipped.
+use melisprint;
+
+SET @manufacturing_hour_exists = (SELECT COUNT(*)
+                                  FROM information_schema.columns
+                                  WHERE table_schema = DATABASE()
+                                    AND table_name = 'product_batches'
+                                    AND column_name = 'manufacturing_hour');
+SET @add_manufacturing_hour = IF(@manufacturing_hour_exists = 0,
+                                 'alter table product_batches add manufacturing_hour int null after manufacturing_date',
+                                 'DO 0');
+PREPARE add_manufacturing_hour FROM @add_manufacturing_hour;
+EXECUTE add_manufacturing_hour;
+DEALLOCATE PREPARE add_manufacturing_hour;
+
+-- Batches already past their due date stay unblocked: the expiry job blocks
+-- them and writes their stock off on its next run.
+SET @blocked_exists = (SELECT COUNT(*)
+                       FROM information_schema.columns
+                       WHERE table_schema = DATABASE()
+                         AND table_name = 'product_batches'
+                         AND column_name = 'blocked');
+SET @add_blocked = IF(@blocked_exists = 0,
+                      'alter table product_batches add blocked boolean not null default false',
+                      'DO 0');
+PREPARE add_blocked FROM @add_blocked;
+EXECUTE add_blocked;
+DEALLOCATE PREPARE add_blocked;
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ExistsWarehouse reports whether a warehouse with the given id exists. It is
+// shared by the repositories that reference warehouses.
+func ExistsWarehouse(ctx context.Context, db *sql.DB, warehouseId int) bool {
+	query := "SELECT id FROM warehouses WHERE id=?;"
+	row := db.QueryRowContext(ctx, query, warehouseId)
+	err := row.Scan(&warehouseId)
+	return err == nil
+}
+
+// IsDuplicateEntry reports whether err is a MySQL unique index violation, which
+// catches concurrent inserts that passed an existence check.
+func IsDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// IsDuplicateKey reports whether err is a violation of the named unique index, for
+// tables with more than one.
+func IsDuplicateKey(err error, key string) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, "'"+key+"'")
+}